@@ -10,6 +10,7 @@ const (
 	RelayModuleCategory     = "RELAY MODULE"
 	BlockAggregatorCategory = "BLOCK AGGREGATOR"
 	ExternalValidatorProxyCategory = "EXTERNAL VALIDATOR PROXY"
+	ChainClockCategory = "CHAIN CLOCK"
 )
 
 func init() {
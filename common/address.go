@@ -0,0 +1,42 @@
+package common
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// MatchListenAddress returns the listen address that target would reach, if any.
+// Hosts match if they are equal, or if they share a port and both are local, such as
+// a target of localhost:18551 and a listen address of 0.0.0.0:18551. Unix socket
+// listen addresses never match.
+func MatchListenAddress(target *url.URL, listenAddresses []string) (string, bool) {
+	if target == nil {
+		return "", false
+	}
+
+	for _, address := range listenAddresses {
+		listenUrl, err := url.Parse(address)
+		if err != nil || listenUrl.Host == "" || listenUrl.Scheme == "unix" {
+			continue
+		}
+
+		if strings.EqualFold(listenUrl.Host, target.Host) {
+			return address, true
+		}
+		if listenUrl.Port() != "" && listenUrl.Port() == target.Port() &&
+			isLocalHost(listenUrl.Hostname()) && isLocalHost(target.Hostname()) {
+			return address, true
+		}
+	}
+
+	return "", false
+}
+
+func isLocalHost(host string) bool {
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
@@ -5,6 +5,7 @@ const (
 	RPCDefaultErrorCode = -32000
 	RPCTimeoutErrorCode = -32001
 	RPCUnmarshalErrorCode = -32002
+	RPCNoContentErrorCode = -32003
 	RPCInternalErrorCode = -32500
 
 )
\ No newline at end of file
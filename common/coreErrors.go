@@ -31,6 +31,7 @@ var (
 	_ Error = new(InvalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(InternalServerError)
+	_ Error = new(NoContentError)
 )
 
 type MethodNotFoundError struct{ Method string }
@@ -99,3 +100,10 @@ type InternalServerError struct {
 func (e *InternalServerError) ErrorCode() int { return e.Code }
 
 func (e *InternalServerError) Error() string { return e.Message }
+
+// NoContentError is used when a request was valid but there is nothing to return, such as no bid for a slot.
+type NoContentError struct{ Message string }
+
+func (e *NoContentError) ErrorCode() int { return RPCNoContentErrorCode }
+
+func (e *NoContentError) Error() string { return e.Message }
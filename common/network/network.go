@@ -0,0 +1,374 @@
+// Package network holds the beacon chain parameters of a network, from the built-in presets or from a
+// consensus-spec config.yaml and genesis data for devnets.
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	Mainnet = "mainnet"
+	Sepolia = "sepolia"
+	Goerli  = "goerli"
+	Holesky = "holesky"
+
+	// farFutureEpoch is the epoch of forks that are not scheduled
+	farFutureEpoch = ^uint64(0)
+)
+
+var (
+	ErrUnknownNetwork  = errors.New("unknown network")
+	ErrInvalidConfig   = errors.New("invalid consensus config")
+	ErrUnsupportedFork = errors.New("fork not supported by the builder types of this build")
+)
+
+// dataVersions are the forks of the presets in fork order, with the version of their data in the builder
+// types of this build. Forks without builder types, such as electra, are scheduled but not supported:
+// their data can not be decoded, so bids and blocks for their slots are refused.
+var dataVersions = []struct {
+	name    string
+	version spec.DataVersion
+}{
+	{"phase0", spec.DataVersionPhase0},
+	{"altair", spec.DataVersionAltair},
+	{"bellatrix", spec.DataVersionBellatrix},
+	{"capella", spec.DataVersionCapella},
+	{"deneb", spec.DataVersionDeneb},
+	{"electra", spec.DataVersionUnknown},
+}
+
+// BuilderForkNames returns the names of the forks from bellatrix, when the builder API starts, including
+// forks scheduled without builder types in this build
+func BuilderForkNames() []string {
+	var names []string
+	builder := false
+	for _, v := range dataVersions {
+		builder = builder || v.version == spec.DataVersionBellatrix
+		if builder {
+			names = append(names, v.name)
+		}
+	}
+	return names
+}
+
+// BuilderDataVersions returns the names of the forks with builder types in this build, from bellatrix
+func BuilderDataVersions() []string {
+	var names []string
+	for _, v := range dataVersions {
+		if v.version >= spec.DataVersionBellatrix {
+			names = append(names, v.name)
+		}
+	}
+	return names
+}
+
+// Fork is a fork of the beacon chain and the epoch it activates at
+type Fork struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Epoch   uint64 `json:"epoch,string"`
+}
+
+// DataVersion returns the version of the data of the fork in the beacon and builder APIs,
+// ErrUnsupportedFork for forks without builder types in this build
+func (f Fork) DataVersion() (spec.DataVersion, error) {
+	for _, v := range dataVersions {
+		if v.name == f.Name && v.version != spec.DataVersionUnknown {
+			return v.version, nil
+		}
+	}
+	return spec.DataVersionUnknown, fmt.Errorf("%w: %s", ErrUnsupportedFork, f.Name)
+}
+
+// Network holds the beacon chain parameters of a network
+type Network struct {
+	Name                  string `json:"name"`
+	GenesisTime           uint64 `json:"genesis_time,string"`
+	GenesisValidatorsRoot string `json:"genesis_validators_root"`
+	GenesisForkVersion    string `json:"genesis_fork_version"`
+	SecondsPerSlot        uint64 `json:"seconds_per_slot,string"`
+	SlotsPerEpoch         uint64 `json:"slots_per_epoch,string"`
+	// Forks are the scheduled forks ordered by epoch, starting with phase0 at genesis
+	Forks []Fork `json:"forks"`
+}
+
+var presets = map[string]Network{
+	Mainnet: {
+		Name:                  Mainnet,
+		GenesisTime:           1606824023,
+		GenesisValidatorsRoot: "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95",
+		GenesisForkVersion:    "0x00000000",
+		SecondsPerSlot:        12,
+		SlotsPerEpoch:         32,
+		Forks: []Fork{
+			{"phase0", "0x00000000", 0},
+			{"altair", "0x01000000", 74240},
+			{"bellatrix", "0x02000000", 144896},
+			{"capella", "0x03000000", 194048},
+			{"deneb", "0x04000000", 269568},
+			{"electra", "0x05000000", 364032},
+		},
+	},
+	Sepolia: {
+		Name:                  Sepolia,
+		GenesisTime:           1655733600,
+		GenesisValidatorsRoot: "0xd8ea171f3c94aea21ebc42a1ed61052acf3f9209c00e4efbaaddac09ed9b8078",
+		GenesisForkVersion:    "0x90000069",
+		SecondsPerSlot:        12,
+		SlotsPerEpoch:         32,
+		Forks: []Fork{
+			{"phase0", "0x90000069", 0},
+			{"altair", "0x90000070", 50},
+			{"bellatrix", "0x90000071", 100},
+			{"capella", "0x90000072", 56832},
+			{"deneb", "0x90000073", 132608},
+			{"electra", "0x90000074", 222464},
+		},
+	},
+	Goerli: {
+		Name:                  Goerli,
+		GenesisTime:           1616508000,
+		GenesisValidatorsRoot: "0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb",
+		GenesisForkVersion:    "0x00001020",
+		SecondsPerSlot:        12,
+		SlotsPerEpoch:         32,
+		Forks: []Fork{
+			{"phase0", "0x00001020", 0},
+			{"altair", "0x01001020", 36660},
+			{"bellatrix", "0x02001020", 112260},
+			{"capella", "0x03001020", 162304},
+			{"deneb", "0x04001020", 231680},
+		},
+	},
+	Holesky: {
+		Name:                  Holesky,
+		GenesisTime:           1695902400,
+		GenesisValidatorsRoot: "0x9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1",
+		GenesisForkVersion:    "0x01017000",
+		SecondsPerSlot:        12,
+		SlotsPerEpoch:         32,
+		Forks: []Fork{
+			{"phase0", "0x01017000", 0},
+			{"altair", "0x02017000", 0},
+			{"bellatrix", "0x03017000", 0},
+			{"capella", "0x04017000", 256},
+			{"deneb", "0x05017000", 29696},
+			{"electra", "0x06017000", 115968},
+		},
+	},
+}
+
+// Names returns the names of the built-in network presets
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preset returns the built-in network preset of the name
+func Preset(name string) (*Network, error) {
+	preset, ok := presets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %s, expected one of %s or a consensus config path", ErrUnknownNetwork, name, strings.Join(Names(), ", "))
+	}
+	n := preset
+	n.Forks = append([]Fork{}, preset.Forks...)
+	return &n, nil
+}
+
+// Load returns the built-in preset of the name, or loads the network from a consensus config.yaml path
+// or a directory holding config.yaml, such as a Kurtosis network-configs directory. A genesis.ssz next to
+// the config provides the genesis time and validators root.
+func Load(value string) (*Network, error) {
+	if _, ok := presets[strings.ToLower(value)]; ok {
+		return Preset(value)
+	}
+
+	info, err := os.Stat(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s, expected one of %s or a consensus config path", ErrUnknownNetwork, value, strings.Join(Names(), ", "))
+	}
+	configPath := value
+	if info.IsDir() {
+		configPath = filepath.Join(value, "config.yaml")
+	}
+
+	genesisPath := filepath.Join(filepath.Dir(configPath), "genesis.ssz")
+	if _, err := os.Stat(genesisPath); err != nil {
+		genesisPath = ""
+	}
+
+	return LoadConfig(configPath, genesisPath)
+}
+
+// consensusConfig are the fields of a consensus-spec config.yaml, kept as text so hex
+// fork versions are not read as numbers
+type consensusConfig struct {
+	ConfigName           string `yaml:"CONFIG_NAME"`
+	PresetBase           string `yaml:"PRESET_BASE"`
+	MinGenesisTime       string `yaml:"MIN_GENESIS_TIME"`
+	GenesisDelay         string `yaml:"GENESIS_DELAY"`
+	SecondsPerSlot       string `yaml:"SECONDS_PER_SLOT"`
+	SlotsPerEpoch        string `yaml:"SLOTS_PER_EPOCH"`
+	GenesisForkVersion   string `yaml:"GENESIS_FORK_VERSION"`
+	AltairForkVersion    string `yaml:"ALTAIR_FORK_VERSION"`
+	AltairForkEpoch      string `yaml:"ALTAIR_FORK_EPOCH"`
+	BellatrixForkVersion string `yaml:"BELLATRIX_FORK_VERSION"`
+	BellatrixForkEpoch   string `yaml:"BELLATRIX_FORK_EPOCH"`
+	CapellaForkVersion   string `yaml:"CAPELLA_FORK_VERSION"`
+	CapellaForkEpoch     string `yaml:"CAPELLA_FORK_EPOCH"`
+	DenebForkVersion     string `yaml:"DENEB_FORK_VERSION"`
+	DenebForkEpoch       string `yaml:"DENEB_FORK_EPOCH"`
+	ElectraForkVersion   string `yaml:"ELECTRA_FORK_VERSION"`
+	ElectraForkEpoch     string `yaml:"ELECTRA_FORK_EPOCH"`
+}
+
+// LoadConfig loads the network from a consensus-spec config.yaml, and the genesis time and validators root from a
+// genesis.ssz state if a path is given. Without genesis data the genesis time is MIN_GENESIS_TIME plus GENESIS_DELAY.
+func LoadConfig(configPath, genesisPath string) (*Network, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var cfg consensusConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrInvalidConfig, configPath, err)
+	}
+
+	n := &Network{Name: cfg.ConfigName, SecondsPerSlot: 12, SlotsPerEpoch: 32}
+	if n.Name == "" {
+		n.Name = filepath.Base(filepath.Dir(configPath))
+	}
+	if cfg.PresetBase == "minimal" {
+		n.SlotsPerEpoch = 8
+	}
+
+	var minGenesisTime, genesisDelay uint64
+	for _, field := range []struct {
+		value string
+		dst   *uint64
+	}{
+		{cfg.MinGenesisTime, &minGenesisTime},
+		{cfg.GenesisDelay, &genesisDelay},
+		{cfg.SecondsPerSlot, &n.SecondsPerSlot},
+		{cfg.SlotsPerEpoch, &n.SlotsPerEpoch},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if *field.dst, err = strconv.ParseUint(field.value, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w %s: %v", ErrInvalidConfig, configPath, err)
+		}
+	}
+	n.GenesisTime = minGenesisTime + genesisDelay
+
+	if !isForkVersion(cfg.GenesisForkVersion) {
+		return nil, fmt.Errorf("%w %s: invalid GENESIS_FORK_VERSION %q", ErrInvalidConfig, configPath, cfg.GenesisForkVersion)
+	}
+	n.GenesisForkVersion = cfg.GenesisForkVersion
+	n.Forks = []Fork{{"phase0", cfg.GenesisForkVersion, 0}}
+	for _, fork := range []struct {
+		name, version, epoch string
+	}{
+		{"altair", cfg.AltairForkVersion, cfg.AltairForkEpoch},
+		{"bellatrix", cfg.BellatrixForkVersion, cfg.BellatrixForkEpoch},
+		{"capella", cfg.CapellaForkVersion, cfg.CapellaForkEpoch},
+		{"deneb", cfg.DenebForkVersion, cfg.DenebForkEpoch},
+		{"electra", cfg.ElectraForkVersion, cfg.ElectraForkEpoch},
+	} {
+		if fork.version == "" || fork.epoch == "" {
+			continue
+		}
+		epoch, err := strconv.ParseUint(fork.epoch, 10, 64)
+		if err != nil || !isForkVersion(fork.version) {
+			return nil, fmt.Errorf("%w %s: invalid %s fork", ErrInvalidConfig, configPath, fork.name)
+		}
+		if epoch == farFutureEpoch {
+			continue
+		}
+		n.Forks = append(n.Forks, Fork{fork.name, fork.version, epoch})
+	}
+
+	if genesisPath != "" {
+		if err := n.loadGenesis(genesisPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// loadGenesis reads the genesis time and validators root, the first fields of the ssz encoded genesis state
+func (n *Network) loadGenesis(genesisPath string) error {
+	f, err := os.Open(genesisPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header := make([]byte, 8+32)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return fmt.Errorf("failed to read genesis state %s: %w", genesisPath, err)
+	}
+	n.GenesisTime = binary.LittleEndian.Uint64(header[:8])
+	n.GenesisValidatorsRoot = hexutil.Encode(header[8:])
+	return nil
+}
+
+func isForkVersion(value string) bool {
+	version, err := hexutil.Decode(value)
+	return err == nil && len(version) == 4
+}
+
+// SlotDuration returns the duration of a slot
+func (n *Network) SlotDuration() time.Duration {
+	return time.Duration(n.SecondsPerSlot) * time.Second
+}
+
+// SlotStart returns the start time of the slot
+func (n *Network) SlotStart(slot uint64) time.Time {
+	return time.Unix(int64(n.GenesisTime+slot*n.SecondsPerSlot), 0)
+}
+
+// Epoch returns the epoch of the slot
+func (n *Network) Epoch(slot uint64) uint64 {
+	return slot / n.SlotsPerEpoch
+}
+
+// ForkAtEpoch returns the fork active at the epoch
+func (n *Network) ForkAtEpoch(epoch uint64) Fork {
+	active := n.Forks[0]
+	for _, fork := range n.Forks[1:] {
+		if fork.Epoch > epoch {
+			break
+		}
+		active = fork
+	}
+	return active
+}
+
+// ForkAtSlot returns the fork active at the slot
+func (n *Network) ForkAtSlot(slot uint64) Fork {
+	return n.ForkAtEpoch(n.Epoch(slot))
+}
+
+// DataVersionAtSlot returns the version of the beacon and builder API data expected at the slot
+func (n *Network) DataVersionAtSlot(slot uint64) (spec.DataVersion, error) {
+	return n.ForkAtSlot(slot).DataVersion()
+}
@@ -0,0 +1,155 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+const testConfig = `# Extends the mainnet preset
+PRESET_BASE: 'mainnet'
+CONFIG_NAME: 'kurtosis'
+
+MIN_GENESIS_TIME: 1700000000
+GENESIS_DELAY: 60
+SECONDS_PER_SLOT: 6
+
+GENESIS_FORK_VERSION: 0x10000038
+ALTAIR_FORK_VERSION: 0x20000038
+ALTAIR_FORK_EPOCH: 0
+BELLATRIX_FORK_VERSION: 0x30000038
+BELLATRIX_FORK_EPOCH: 0
+CAPELLA_FORK_VERSION: 0x40000038
+CAPELLA_FORK_EPOCH: 0
+DENEB_FORK_VERSION: 0x50000038
+DENEB_FORK_EPOCH: 4
+ELECTRA_FORK_VERSION: 0x60000038
+ELECTRA_FORK_EPOCH: 18446744073709551615
+
+BLOB_SCHEDULE:
+  - EPOCH: 4
+    MAX_BLOBS_PER_BLOCK: 6
+`
+
+func TestPreset(t *testing.T) {
+	for _, name := range Names() {
+		n, err := Preset(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.GenesisTime == 0 || n.SecondsPerSlot != 12 || n.Forks[0].Version != n.GenesisForkVersion {
+			t.Errorf("Unexpected %s preset %+v", name, n)
+		}
+	}
+
+	holesky, err := Load("Holesky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if holesky.GenesisForkVersion != "0x01017000" || holesky.ForkAtEpoch(300).Name != "capella" {
+		t.Errorf("Unexpected holesky preset %+v", holesky)
+	}
+
+	// Presets are copies
+	holesky.Forks[0].Version = "0x00000000"
+	if again, _ := Preset(Holesky); again.Forks[0].Version != "0x01017000" {
+		t.Error("Expected changes to a loaded preset not to change the preset")
+	}
+
+	if _, err := Load("unknown"); !errors.Is(err, ErrUnknownNetwork) {
+		t.Errorf("Expected an unknown network, got %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Name != "kurtosis" || n.GenesisTime != 1700000060 || n.SecondsPerSlot != 6 || n.SlotsPerEpoch != 32 || n.GenesisForkVersion != "0x10000038" {
+		t.Errorf("Unexpected network %+v", n)
+	}
+	if len(n.Forks) != 5 {
+		t.Errorf("Expected the unscheduled electra fork to be left out, got %+v", n.Forks)
+	}
+	if fork := n.ForkAtEpoch(3); fork.Name != "capella" {
+		t.Errorf("Expected capella before the deneb fork epoch, got %s", fork.Name)
+	}
+	if fork := n.ForkAtEpoch(n.Epoch(4 * 32)); fork.Name != "deneb" || fork.Version != "0x50000038" {
+		t.Errorf("Expected deneb at its fork epoch, got %+v", fork)
+	}
+	if start := n.SlotStart(10); start.Unix() != 1700000060+60 {
+		t.Errorf("Unexpected slot start %s", start)
+	}
+
+	// The genesis state provides the actual genesis time and validators root
+	genesis := make([]byte, 100)
+	binary.LittleEndian.PutUint64(genesis, 1700000123)
+	genesis[8] = 0xab
+	if err := os.WriteFile(filepath.Join(dir, "genesis.ssz"), genesis, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	n, err = Load(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.GenesisTime != 1700000123 || n.GenesisValidatorsRoot != "0xab00000000000000000000000000000000000000000000000000000000000000" {
+		t.Errorf("Expected the genesis data from genesis.ssz, got %+v", n)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("GENESIS_FORK_VERSION: 0x01\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected an invalid config, got %v", err)
+	}
+}
+
+func TestDataVersionAtSlot(t *testing.T) {
+	mainnet, err := Preset(Mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		slot    uint64
+		version spec.DataVersion
+		err     error
+	}{
+		{0, spec.DataVersionPhase0, nil},
+		{144896*32 - 1, spec.DataVersionAltair, nil},
+		{144896 * 32, spec.DataVersionBellatrix, nil},
+		{269568 * 32, spec.DataVersionDeneb, nil},
+		{364032*32 - 1, spec.DataVersionDeneb, nil},
+		{364032 * 32, spec.DataVersionUnknown, ErrUnsupportedFork},
+	}
+	for _, c := range cases {
+		version, err := mainnet.DataVersionAtSlot(c.slot)
+		if version != c.version || !errors.Is(err, c.err) {
+			t.Errorf("Expected slot %d to be %s with error %v, got %s with error %v", c.slot, c.version, c.err, version, err)
+		}
+	}
+}
+
+func TestBuilderDataVersions(t *testing.T) {
+	versions := BuilderDataVersions()
+	if strings.Join(versions, ",") != "bellatrix,capella,deneb" {
+		t.Errorf("Expected the builder versions from bellatrix, got %v", versions)
+	}
+	if names := BuilderForkNames(); strings.Join(names, ",") != "bellatrix,capella,deneb,electra" {
+		t.Errorf("Expected the builder forks from bellatrix, got %v", names)
+	}
+	if _, err := (Fork{Name: "electra"}).DataVersion(); !errors.Is(err, ErrUnsupportedFork) {
+		t.Errorf("Expected electra to be unsupported, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+
+	denebApi "github.com/attestantio/go-builder-client/api/deneb"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+var (
+	ErrBlobsBundleMismatch = errors.New("blobs bundle does not match the blinded block commitments")
+	ErrInvalidBlobProof    = errors.New("invalid blob proof")
+)
+
+// VerifyBlobsBundle checks the blobs bundle holds a blob and proof for each commitment of the blinded block,
+// and verifies the KZG proof of each blob against its commitment with the mainnet trusted setup
+func VerifyBlobsBundle(bundle *denebApi.BlobsBundle, commitments []deneb.KZGCommitment) error {
+	if bundle == nil {
+		if len(commitments) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: no blobs bundle for %d commitments", ErrBlobsBundleMismatch, len(commitments))
+	}
+
+	if len(bundle.Commitments) != len(commitments) || len(bundle.Blobs) != len(commitments) || len(bundle.Proofs) != len(commitments) {
+		return fmt.Errorf("%w: %d commitments, %d blobs and %d proofs for %d commitments", ErrBlobsBundleMismatch, len(bundle.Commitments), len(bundle.Blobs), len(bundle.Proofs), len(commitments))
+	}
+
+	for i, commitment := range bundle.Commitments {
+		if commitment != commitments[i] {
+			return fmt.Errorf("%w: commitment %s at index %d, expected %s", ErrBlobsBundleMismatch, commitment.String(), i, commitments[i].String())
+		}
+		if err := kzg4844.VerifyBlobProof(kzg4844.Blob(bundle.Blobs[i]), kzg4844.Commitment(commitment), kzg4844.Proof(bundle.Proofs[i])); err != nil {
+			return fmt.Errorf("%w at index %d: %v", ErrInvalidBlobProof, i, err)
+		}
+	}
+
+	return nil
+}
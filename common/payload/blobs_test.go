@@ -0,0 +1,67 @@
+package payload
+
+import (
+	"errors"
+	"testing"
+
+	denebApi "github.com/attestantio/go-builder-client/api/deneb"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// testBlob returns a blob of small field elements with its commitment and proof
+func testBlob(t *testing.T, seed byte) (deneb.Blob, deneb.KZGCommitment, deneb.KZGProof) {
+	var blob kzg4844.Blob
+	for i := 0; i < len(blob)/32; i++ {
+		blob[i*32+31] = seed + byte(i)
+	}
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deneb.Blob(blob), deneb.KZGCommitment(commitment), deneb.KZGProof(proof)
+}
+
+func TestVerifyBlobsBundle(t *testing.T) {
+	blob1, commitment1, proof1 := testBlob(t, 1)
+	blob2, commitment2, proof2 := testBlob(t, 2)
+	commitments := []deneb.KZGCommitment{commitment1, commitment2}
+
+	bundle := &denebApi.BlobsBundle{
+		Commitments: commitments,
+		Proofs:      []deneb.KZGProof{proof1, proof2},
+		Blobs:       []deneb.Blob{blob1, blob2},
+	}
+	if err := VerifyBlobsBundle(bundle, commitments); err != nil {
+		t.Fatalf("Expected the blobs bundle to verify, got %v", err)
+	}
+
+	if err := VerifyBlobsBundle(nil, nil); err != nil {
+		t.Errorf("Expected no blobs bundle to verify for a block without commitments, got %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		bundle      *denebApi.BlobsBundle
+		commitments []deneb.KZGCommitment
+		err         error
+	}{
+		{"MissingBundle", nil, commitments, ErrBlobsBundleMismatch},
+		{"MissingBlob", &denebApi.BlobsBundle{Commitments: commitments, Proofs: bundle.Proofs, Blobs: bundle.Blobs[:1]}, commitments, ErrBlobsBundleMismatch},
+		{"ExtraCommitment", bundle, commitments[:1], ErrBlobsBundleMismatch},
+		{"OtherCommitment", bundle, []deneb.KZGCommitment{commitment2, commitment1}, ErrBlobsBundleMismatch},
+		{"SwappedProofs", &denebApi.BlobsBundle{Commitments: commitments, Proofs: []deneb.KZGProof{proof2, proof1}, Blobs: bundle.Blobs}, commitments, ErrInvalidBlobProof},
+		{"SwappedBlobs", &denebApi.BlobsBundle{Commitments: commitments, Proofs: bundle.Proofs, Blobs: []deneb.Blob{blob2, blob1}}, commitments, ErrInvalidBlobProof},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := VerifyBlobsBundle(c.bundle, c.commitments); !errors.Is(err, c.err) {
+				t.Errorf("Expected %v, got %v", c.err, err)
+			}
+		})
+	}
+}
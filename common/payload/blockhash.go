@@ -0,0 +1,123 @@
+// Package payload verifies execution payloads returned by block sources against the blinded blocks they unblind.
+package payload
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	ErrNoPayload         = errors.New("no execution payload set")
+	ErrBlockHashMismatch = errors.New("block hash does not match the payload contents")
+)
+
+// rawTransactions are transactions in their consensus encoding, which is how they are stored in the
+// transactions trie, so the root is derived without decoding them
+type rawTransactions [][]byte
+
+func (t rawTransactions) Len() int { return len(t) }
+
+func (t rawTransactions) EncodeIndex(i int, w *bytes.Buffer) { w.Write(t[i]) }
+
+// Version returns the fork version of the payload
+func Version(payload *commonTypes.VersionedExecutionPayloadV2) (spec.DataVersion, error) {
+	switch {
+	case payload == nil:
+		return spec.DataVersionUnknown, ErrNoPayload
+	case payload.Deneb != nil && payload.Deneb.ExecutionPayload != nil:
+		return spec.DataVersionDeneb, nil
+	case payload.Capella != nil:
+		return spec.DataVersionCapella, nil
+	case payload.Bellatrix != nil:
+		return spec.DataVersionBellatrix, nil
+	default:
+		return spec.DataVersionUnknown, ErrNoPayload
+	}
+}
+
+// Header reconstructs the execution block header of the payload. The parent beacon block root is the
+// parent root of the beacon block holding the payload and is only part of the header from Deneb.
+func Header(payload *commonTypes.VersionedExecutionPayloadV2, parentBeaconBlockRoot phase0.Root) (*types.Header, error) {
+	version, err := Version(payload)
+	if err != nil {
+		return nil, err
+	}
+	base, err := payload.ToBaseExecutionPayload()
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make(rawTransactions, len(base.Transactions))
+	for i, tx := range base.Transactions {
+		transactions[i] = tx
+	}
+
+	header := &types.Header{
+		ParentHash:  common.Hash(base.ParentHash),
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    common.Address(base.FeeRecipient),
+		Root:        common.Hash(base.StateRoot),
+		TxHash:      types.DeriveSha(transactions, trie.NewStackTrie(nil)),
+		ReceiptHash: common.Hash(base.ReceiptsRoot),
+		Bloom:       types.Bloom(base.LogsBloom),
+		Difficulty:  big.NewInt(0),
+		Number:      new(big.Int).SetUint64(base.BlockNumber),
+		GasLimit:    base.GasLimit,
+		GasUsed:     base.GasUsed,
+		Time:        base.Timestamp,
+		Extra:       base.ExtraData,
+		MixDigest:   common.Hash(base.PrevRandao),
+		BaseFee:     base.BaseFeePerGas.ToBig(),
+	}
+
+	if version >= spec.DataVersionCapella {
+		withdrawals := make(types.Withdrawals, len(base.Withdrawals))
+		for i, withdrawal := range base.Withdrawals {
+			withdrawals[i] = &types.Withdrawal{
+				Index:     uint64(withdrawal.Index),
+				Validator: uint64(withdrawal.ValidatorIndex),
+				Address:   common.Address(withdrawal.Address),
+				Amount:    uint64(withdrawal.Amount),
+			}
+		}
+		withdrawalsHash := types.DeriveSha(withdrawals, trie.NewStackTrie(nil))
+		header.WithdrawalsHash = &withdrawalsHash
+	}
+
+	if version >= spec.DataVersionDeneb {
+		blobGasUsed, excessBlobGas := base.BlobGasUsed, base.ExcessBlobGas
+		parentRoot := common.Hash(parentBeaconBlockRoot)
+		header.BlobGasUsed = &blobGasUsed
+		header.ExcessBlobGas = &excessBlobGas
+		header.ParentBeaconRoot = &parentRoot
+	}
+
+	return header, nil
+}
+
+// VerifyBlockHash recomputes the execution block hash from the payload contents and checks it is the
+// block hash the payload claims
+func VerifyBlockHash(payload *commonTypes.VersionedExecutionPayloadV2, parentBeaconBlockRoot phase0.Root) error {
+	header, err := Header(payload, parentBeaconBlockRoot)
+	if err != nil {
+		return err
+	}
+	base, err := payload.ToBaseExecutionPayload()
+	if err != nil {
+		return err
+	}
+
+	if computed := header.Hash(); computed != common.Hash(base.BlockHash) {
+		return fmt.Errorf("%w: computed %s, payload has %s", ErrBlockHashMismatch, computed.String(), base.BlockHash.String())
+	}
+	return nil
+}
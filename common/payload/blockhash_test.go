@@ -0,0 +1,215 @@
+package payload
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	denebApi "github.com/attestantio/go-builder-client/api/deneb"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// testBlock is a block with a legacy and an access list transaction from the go-ethereum block encoding tests,
+// testTxRoot is the root the go-ethereum stack trie derives for its transactions
+const testBlock = "f90319f90211a00000000000000000000000000000000000000000000000000000000000000000a01dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347948888f1f195afa192cfee860698584c030f4c9db1a0ef1552a40b7165c3cd773806b9e0c165b75356e0314bf0706f279c729f51e017a0e6e49996c7ec59f7a23d22b83239a60151512c65613bf84a0d7da336399ebc4aa0cafe75574d59780665a97fbfd11365c7545aa8f1abf4e5e12e8243334ef7286bb901000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000083020000820200832fefd882a410845506eb0796636f6f6c65737420626c6f636b206f6e20636861696ea0bd4472abb6659ebe3ee06ee4d7b72a00a9f4d001caca51342001075469aff49888a13a5a8c8f2bb1c4f90101f85f800a82c35094095e7baea6a6c7c4c2dfeb977efac326af552d870a801ba09bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094fa08a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b1b89e01f89b01800a8301e24194095e7baea6a6c7c4c2dfeb977efac326af552d878080f838f7940000000000000000000000000000000000000001e1a0000000000000000000000000000000000000000000000000000000000000000001a03dbacc8d0259f2508625e97fdfc57cd85fdd16e5821bc2c10bdd1a52649e8335a0476e10695b183a87b0aa292a7f4b78ef0c3fbe62aa2c42c84e1d9c3da159ef14c0"
+
+const testTxRoot = "0x3cb6deb616ff113b756d426828b841be06043f4ea00138e977fc3eb290c01443"
+
+func testTransactions(t *testing.T) ([]bellatrix.Transaction, common.Hash) {
+	var block types.Block
+	if err := rlp.DecodeBytes(common.FromHex(testBlock), &block); err != nil {
+		t.Fatal(err)
+	}
+	var transactions []bellatrix.Transaction
+	for _, tx := range block.Transactions() {
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		transactions = append(transactions, encoded)
+	}
+	return transactions, common.HexToHash(testTxRoot)
+}
+
+// referenceRoot returns the root of the items keyed by their rlp encoded index, from the database backed trie of
+// go-ethereum rather than the stack trie the payload roots are derived with
+func referenceRoot(t *testing.T, items [][]byte) common.Hash {
+	tr := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	for i, item := range items {
+		key, err := rlp.EncodeToBytes(uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Update(key, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tr.Hash()
+}
+
+// manyTransactions returns more than 128 transactions, so their keys span one and two byte rlp indexes
+func manyTransactions(t *testing.T) []bellatrix.Transaction {
+	transactions, _ := testTransactions(t)
+	many := make([]bellatrix.Transaction, 0, 300)
+	for i := 0; i < 300; i++ {
+		many = append(many, transactions[i%len(transactions)])
+	}
+	return many
+}
+
+// manyWithdrawals returns more than 128 withdrawals with their consensus encoding
+func manyWithdrawals(t *testing.T) ([]*capella.Withdrawal, [][]byte) {
+	var withdrawals []*capella.Withdrawal
+	var encoded [][]byte
+	for i := 0; i < 200; i++ {
+		withdrawal := &capella.Withdrawal{Index: capella.WithdrawalIndex(1000 + i), ValidatorIndex: phase0.ValidatorIndex(i * 3), Address: bellatrix.ExecutionAddress{byte(i)}, Amount: phase0.Gwei(i + 1)}
+		raw, err := rlp.EncodeToBytes(&types.Withdrawal{Index: uint64(withdrawal.Index), Validator: uint64(withdrawal.ValidatorIndex), Address: common.Address(withdrawal.Address), Amount: uint64(withdrawal.Amount)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		withdrawals = append(withdrawals, withdrawal)
+		encoded = append(encoded, raw)
+	}
+	return withdrawals, encoded
+}
+
+func TestTransactionsRoot(t *testing.T) {
+	transactions, txHash := testTransactions(t)
+
+	raw := make(rawTransactions, len(transactions))
+	for i, tx := range transactions {
+		raw[i] = tx
+	}
+	if root := types.DeriveSha(raw, trie.NewStackTrie(nil)); root != txHash {
+		t.Errorf("Expected the transactions root %s, got %s", txHash.String(), root.String())
+	}
+	if root := types.DeriveSha(rawTransactions{}, trie.NewStackTrie(nil)); root != types.EmptyRootHash {
+		t.Errorf("Expected the empty root for no transactions, got %s", root.String())
+	}
+
+	many := manyTransactions(t)
+	raw = make(rawTransactions, len(many))
+	items := make([][]byte, len(many))
+	for i, tx := range many {
+		raw[i] = tx
+		items[i] = tx
+	}
+	if root, expected := types.DeriveSha(raw, trie.NewStackTrie(nil)), referenceRoot(t, items); root != expected {
+		t.Errorf("Expected the root of %d transactions %s, got %s", len(many), expected.String(), root.String())
+	}
+}
+
+// testPayload returns a payload of the fork version and the block hash of its header built independently
+func testPayload(t *testing.T, version spec.DataVersion, parentBeaconBlockRoot phase0.Root) (*commonTypes.VersionedExecutionPayloadV2, phase0.Hash32) {
+	transactions := manyTransactions(t)
+	items := make([][]byte, len(transactions))
+	for i, tx := range transactions {
+		items[i] = tx
+	}
+	txHash := referenceRoot(t, items)
+	withdrawals, encodedWithdrawals := manyWithdrawals(t)
+
+	header := &types.Header{
+		ParentHash:  common.Hash{0x01},
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    common.Address{0x02},
+		Root:        common.Hash{0x03},
+		TxHash:      txHash,
+		ReceiptHash: common.Hash{0x04},
+		Difficulty:  big.NewInt(0),
+		Number:      big.NewInt(100),
+		GasLimit:    30000000,
+		GasUsed:     42000,
+		Time:        1700000000,
+		Extra:       []byte("builder"),
+		MixDigest:   common.Hash{0x05},
+		BaseFee:     big.NewInt(7),
+	}
+	if version >= spec.DataVersionCapella {
+		withdrawalsHash := referenceRoot(t, encodedWithdrawals)
+		header.WithdrawalsHash = &withdrawalsHash
+	}
+	if version >= spec.DataVersionDeneb {
+		blobGasUsed, excessBlobGas, parentRoot := uint64(131072), uint64(0), common.Hash(parentBeaconBlockRoot)
+		header.BlobGasUsed = &blobGasUsed
+		header.ExcessBlobGas = &excessBlobGas
+		header.ParentBeaconRoot = &parentRoot
+	}
+	blockHash := phase0.Hash32(header.Hash())
+
+	baseFee := [32]byte{7}
+	payload := &commonTypes.VersionedExecutionPayloadV2{}
+	switch version {
+	case spec.DataVersionBellatrix:
+		payload.Bellatrix = &bellatrix.ExecutionPayload{
+			ParentHash: phase0.Hash32{0x01}, FeeRecipient: bellatrix.ExecutionAddress{0x02}, StateRoot: [32]byte{0x03}, ReceiptsRoot: [32]byte{0x04},
+			PrevRandao: [32]byte{0x05}, BlockNumber: 100, GasLimit: 30000000, GasUsed: 42000, Timestamp: 1700000000,
+			ExtraData: []byte("builder"), BaseFeePerGas: baseFee, BlockHash: blockHash, Transactions: transactions,
+		}
+	case spec.DataVersionCapella:
+		payload.Capella = &capella.ExecutionPayload{
+			ParentHash: phase0.Hash32{0x01}, FeeRecipient: bellatrix.ExecutionAddress{0x02}, StateRoot: [32]byte{0x03}, ReceiptsRoot: [32]byte{0x04},
+			PrevRandao: [32]byte{0x05}, BlockNumber: 100, GasLimit: 30000000, GasUsed: 42000, Timestamp: 1700000000,
+			ExtraData: []byte("builder"), BaseFeePerGas: baseFee, BlockHash: blockHash, Transactions: transactions, Withdrawals: withdrawals,
+		}
+	case spec.DataVersionDeneb:
+		payload.Deneb = &denebApi.ExecutionPayloadAndBlobsBundle{
+			ExecutionPayload: &deneb.ExecutionPayload{
+				ParentHash: phase0.Hash32{0x01}, FeeRecipient: bellatrix.ExecutionAddress{0x02}, StateRoot: [32]byte{0x03}, ReceiptsRoot: [32]byte{0x04},
+				PrevRandao: [32]byte{0x05}, BlockNumber: 100, GasLimit: 30000000, GasUsed: 42000, Timestamp: 1700000000,
+				ExtraData: []byte("builder"), BaseFeePerGas: uint256.NewInt(7), BlockHash: blockHash, Transactions: transactions, Withdrawals: withdrawals,
+				BlobGasUsed: 131072, ExcessBlobGas: 0,
+			},
+			BlobsBundle: &denebApi.BlobsBundle{},
+		}
+	}
+	return payload, blockHash
+}
+
+func TestVerifyBlockHash(t *testing.T) {
+	parentBeaconBlockRoot := phase0.Root{0x0c}
+
+	for _, version := range []spec.DataVersion{spec.DataVersionBellatrix, spec.DataVersionCapella, spec.DataVersionDeneb} {
+		t.Run(version.String(), func(t *testing.T) {
+			payload, _ := testPayload(t, version, parentBeaconBlockRoot)
+			if err := VerifyBlockHash(payload, parentBeaconBlockRoot); err != nil {
+				t.Fatalf("Expected the block hash to verify, got %v", err)
+			}
+
+			tampered, _ := testPayload(t, version, parentBeaconBlockRoot)
+			switch version {
+			case spec.DataVersionBellatrix:
+				tampered.Bellatrix.GasUsed++
+			case spec.DataVersionCapella:
+				tampered.Capella.Withdrawals[0].Amount++
+			case spec.DataVersionDeneb:
+				tampered.Deneb.ExecutionPayload.Transactions = tampered.Deneb.ExecutionPayload.Transactions[:1]
+			}
+			if err := VerifyBlockHash(tampered, parentBeaconBlockRoot); !errors.Is(err, ErrBlockHashMismatch) {
+				t.Errorf("Expected a block hash mismatch for a tampered payload, got %v", err)
+			}
+		})
+	}
+
+	t.Run("ParentBeaconBlockRoot", func(t *testing.T) {
+		payload, _ := testPayload(t, spec.DataVersionDeneb, parentBeaconBlockRoot)
+		if err := VerifyBlockHash(payload, phase0.Root{}); !errors.Is(err, ErrBlockHashMismatch) {
+			t.Errorf("Expected a block hash mismatch for another parent beacon block root, got %v", err)
+		}
+	})
+
+	if err := VerifyBlockHash(&commonTypes.VersionedExecutionPayloadV2{}, parentBeaconBlockRoot); !errors.Is(err, ErrNoPayload) {
+		t.Errorf("Expected no payload error, got %v", err)
+	}
+}
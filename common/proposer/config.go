@@ -0,0 +1,236 @@
+// Package proposer holds the per-validator configuration of block sources, relays, min-bid, bid selection and builders.
+package proposer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Selection is the strategy used to choose between bids from different block sources
+type Selection string
+
+const (
+	// SelectionHighestValue selects the most valuable bid from any block source
+	SelectionHighestValue Selection = "highest-value"
+	// SelectionSourcePriority selects the most valuable bid from the first block source
+	// listed in BlockSources that returned a bid
+	SelectionSourcePriority Selection = "source-priority"
+)
+
+// Options are the settings that apply to a proposer. Unset fields fall back to the default section.
+type Options struct {
+	// BlockSources are the block source modules used for the proposer, nil for all connected block sources
+	BlockSources []string `json:"block_sources"`
+	// Relays are the relay entries of the relay module used for the proposer, nil for all configured relays
+	Relays []string `json:"relays"`
+	// MinBid is the minimum bid value accepted for the proposer in wei
+	MinBid string `json:"min_bid,omitempty"`
+	// Selection is the bid selection strategy for the proposer
+	Selection Selection `json:"selection,omitempty"`
+	// BuilderAllowlist are the only builder pubkeys whose bids are accepted for the proposer, nil for any builder
+	BuilderAllowlist []string `json:"builder_allowlist"`
+	// BuilderDenylist are builder pubkeys whose bids are refused for the proposer
+	BuilderDenylist []string `json:"builder_denylist"`
+}
+
+// Config is the proposer configuration, keyed by proposer pubkey with a default section
+type Config struct {
+	Default   Options            `json:"default"`
+	Proposers map[string]Options `json:"proposers,omitempty"`
+}
+
+// Load reads and validates the proposer configuration JSON file at path
+func Load(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cfg Config
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid proposer config %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid proposer config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks every section of the configuration and normalizes the proposer pubkeys to lowercase
+func (c *Config) Validate() error {
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+
+	proposers := make(map[string]Options, len(c.Proposers))
+	for pubkey, options := range c.Proposers {
+		key := strings.ToLower(pubkey)
+		if !IsPubkey(key) {
+			return fmt.Errorf("invalid proposer pubkey %s", pubkey)
+		}
+		if _, ok := proposers[key]; ok {
+			return fmt.Errorf("duplicate proposer pubkey %s", pubkey)
+		}
+		if err := options.validate(); err != nil {
+			return fmt.Errorf("proposer %s: %w", pubkey, err)
+		}
+		proposers[key] = options
+	}
+	c.Proposers = proposers
+
+	return nil
+}
+
+func (o Options) validate() error {
+	if o.MinBid != "" {
+		if _, err := parseMinBid(o.MinBid); err != nil {
+			return err
+		}
+	}
+
+	switch o.Selection {
+	case "", SelectionHighestValue, SelectionSourcePriority:
+	default:
+		return fmt.Errorf("unknown selection strategy %s", o.Selection)
+	}
+
+	for _, pubkey := range append(append([]string{}, o.BuilderAllowlist...), o.BuilderDenylist...) {
+		if !IsPubkey(pubkey) {
+			return fmt.Errorf("invalid builder pubkey %s", pubkey)
+		}
+	}
+
+	return nil
+}
+
+// For returns the options for the proposer, with unset fields taken from the default section.
+// A nil configuration returns empty options, which allow every block source and relay.
+func (c *Config) For(pubkey string) Options {
+	if c == nil {
+		return Options{}
+	}
+
+	options := c.Default
+	proposer, ok := c.Proposers[strings.ToLower(pubkey)]
+	if !ok {
+		return options
+	}
+
+	if proposer.BlockSources != nil {
+		options.BlockSources = proposer.BlockSources
+	}
+	if proposer.Relays != nil {
+		options.Relays = proposer.Relays
+	}
+	if proposer.MinBid != "" {
+		options.MinBid = proposer.MinBid
+	}
+	if proposer.Selection != "" {
+		options.Selection = proposer.Selection
+	}
+	if proposer.BuilderAllowlist != nil {
+		options.BuilderAllowlist = proposer.BuilderAllowlist
+	}
+	if proposer.BuilderDenylist != nil {
+		options.BuilderDenylist = proposer.BuilderDenylist
+	}
+
+	return options
+}
+
+// RelayValues returns every relay named in the configuration
+func (c *Config) RelayValues() []string {
+	if c == nil {
+		return nil
+	}
+
+	relays := append([]string{}, c.Default.Relays...)
+	for _, options := range c.Proposers {
+		relays = append(relays, options.Relays...)
+	}
+	return relays
+}
+
+// AllowsBlockSource returns whether the block source module can be used
+func (o Options) AllowsBlockSource(moduleName string) bool {
+	return o.BlockSources == nil || o.blockSourcePriority(moduleName) >= 0
+}
+
+// BlockSourcePriority returns the position of the block source in BlockSources,
+// lower is preferred. Block sources that are not listed come last.
+func (o Options) BlockSourcePriority(moduleName string) int {
+	priority := o.blockSourcePriority(moduleName)
+	if priority < 0 {
+		return len(o.BlockSources)
+	}
+	return priority
+}
+
+func (o Options) blockSourcePriority(moduleName string) int {
+	for i, blockSource := range o.BlockSources {
+		if blockSource == moduleName {
+			return i
+		}
+	}
+	return -1
+}
+
+// AllowsBuilder returns whether bids from the builder pubkey are accepted for the proposer
+func (o Options) AllowsBuilder(pubkey string) bool {
+	if containsPubkey(o.BuilderDenylist, pubkey) {
+		return false
+	}
+	return o.BuilderAllowlist == nil || containsPubkey(o.BuilderAllowlist, pubkey)
+}
+
+// IsPubkey returns whether the value is a 0x prefixed hex BLS pubkey
+func IsPubkey(value string) bool {
+	if len(value) != 98 || !strings.HasPrefix(value, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(value[2:])
+	return err == nil
+}
+
+func containsPubkey(pubkeys []string, pubkey string) bool {
+	for _, p := range pubkeys {
+		if strings.EqualFold(p, pubkey) {
+			return true
+		}
+	}
+	return false
+}
+
+// MinBidValue returns the minimum bid in wei, nil if no minimum is set
+func (o Options) MinBidValue() *big.Int {
+	if o.MinBid == "" {
+		return nil
+	}
+	minBid, _ := parseMinBid(o.MinBid)
+	return minBid
+}
+
+// SelectionStrategy returns the bid selection strategy, highest value if not set
+func (o Options) SelectionStrategy() Selection {
+	if o.Selection == "" {
+		return SelectionHighestValue
+	}
+	return o.Selection
+}
+
+func parseMinBid(value string) (*big.Int, error) {
+	minBid, ok := new(big.Int).SetString(value, 10)
+	if !ok || minBid.Sign() < 0 {
+		return nil, fmt.Errorf("invalid min bid %s", value)
+	}
+	return minBid, nil
+}
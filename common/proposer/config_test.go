@@ -0,0 +1,84 @@
+package proposer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPubkey = "0x8a1d7b8dd64e0aafe7ea7b6c95065c9364cf99d38470c12ee807d55f7de1529ad29ce2c422e0b65e3d5a05c02caca249"
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "proposer-config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{
+		"default": {"block_sources": ["relay"], "min_bid": "100", "selection": "highest-value"},
+		"proposers": {
+			"0x8A1D7B8DD64E0AAFE7EA7B6C95065C9364CF99D38470C12EE807D55F7DE1529AD29CE2C422E0B65E3D5A05C02CACA249": {
+				"relays": [],
+				"selection": "source-priority"
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := cfg.For(testPubkey)
+	if options.SelectionStrategy() != SelectionSourcePriority || options.MinBidValue().Int64() != 100 {
+		t.Errorf("Expected the proposer options merged with the default section, got %+v", options)
+	}
+	if options.Relays == nil || len(options.Relays) != 0 {
+		t.Errorf("Expected an empty relay list to be kept, got %v", options.Relays)
+	}
+	if !options.AllowsBlockSource("relay") || options.AllowsBlockSource("builder") {
+		t.Errorf("Expected the default block sources to apply, got %v", options.BlockSources)
+	}
+
+	if other := cfg.For("0x" + testPubkey[4:] + "00"); other.Relays != nil || other.Selection != SelectionHighestValue {
+		t.Errorf("Expected the default section for other proposers, got %+v", other)
+	}
+
+	var nilConfig *Config
+	if options := nilConfig.For(testPubkey); !options.AllowsBlockSource("builder") || options.MinBidValue() != nil {
+		t.Errorf("Expected no restrictions without a proposer config, got %+v", options)
+	}
+
+	invalid := []string{
+		`{"default": {"min_bid": "-1"}}`,
+		`{"default": {"selection": "random"}}`,
+		`{"proposers": {"0x1234": {}}}`,
+		`{"default": {"unknown": true}}`,
+		`{"default": {"builder_denylist": ["0x1234"]}}`,
+	}
+	for _, content := range invalid {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %s", content)
+		}
+	}
+}
+
+func TestAllowsBuilder(t *testing.T) {
+	builder := "0x" + testPubkey[4:] + "00"
+
+	if !(Options{}).AllowsBuilder(builder) {
+		t.Error("Expected any builder to be accepted without lists")
+	}
+	if (Options{BuilderDenylist: []string{"0x" + strings.ToUpper(builder[2:])}}).AllowsBuilder(builder) {
+		t.Error("Expected a denied builder to be refused")
+	}
+	allowlist := Options{BuilderAllowlist: []string{testPubkey}}
+	if !allowlist.AllowsBuilder(testPubkey) || allowlist.AllowsBuilder(builder) {
+		t.Error("Expected only the builders on the allow list to be accepted")
+	}
+	if (Options{BuilderAllowlist: []string{}}).AllowsBuilder(builder) {
+		t.Error("Expected an empty allow list to refuse every builder")
+	}
+}
@@ -91,6 +91,12 @@ func (c *Client) Ping(message string) error {
 	return nil
 }
 
+// HasCallback reports whether the method is served by a module connected to the core
+func (c *Client) HasCallback(method string) bool {
+	_, ok := c.knownCallbacks[method]
+	return ok
+}
+
 // Close closes the client, aborting any in-flight requests.
 func (c *Client) Close() {
 	select {
@@ -110,8 +116,8 @@ func (c *Client) dispatch() {
 		reqInitLock = c.reqInit // nil while the send lock is held
 	)
 
+	// close is left open, concurrent Close calls may still be sending on it and return on closed instead
 	defer func() {
-		close(c.close)
 		c.handler.close(ErrClientQuit, nil)
 		close(c.commChannels.Incoming)
 		close(c.commChannels.Outgoing)
@@ -155,16 +161,21 @@ func (c *Client) dispatch() {
 
 // read decodes RPC messages from the incoming connection and
 // sends them to the dispatch loop for handling.
+// It only waits on closed, receiving from close would take the signal meant for the dispatch loop.
 func (c *Client) read(incomingChan chan JsonRPCMessage) {
 	for {
 		select {
-		case <-c.close:
+		case <-c.closed:
 			return
-		default:
-			msg := <-incomingChan
-			c.readOp <- readOp{msg: &msg}
+		case msg, ok := <-incomingChan:
+			if !ok {
+				return
+			}
+			select {
+			case c.readOp <- readOp{msg: &msg}:
+			case <-c.closed:
+				return
+			}
 		}
-
 	}
-
 }
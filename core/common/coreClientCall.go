@@ -89,7 +89,7 @@ func (c *Client) send(ctx context.Context, op *requestOp, msg interface{}) error
 	case <-ctx.Done():
 		// This can happen if the client is overloaded.
 		return ctx.Err()
-	case <-c.close:
+	case <-c.closed:
 		return ErrClientQuit
 	}
 }
@@ -110,8 +110,8 @@ func (op *requestOp) wait(ctx context.Context, c *Client) (*JsonRPCMessage, erro
 		select {
 		case c.reqTimeout <- op:
 			// Put and wait for request to be removed from the handler.
-		case <-c.close:
-			// If client is called to close, stop waiting and return.
+		case <-c.closed:
+			// If the client is closed, stop waiting and return.
 		}
 		return nil, ctx.Err()
 	case resp := <-op.resp:
@@ -0,0 +1,131 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pon-network/mev-plus/common"
+)
+
+// newTestClient returns a client with its incoming channel filled with notifications, which the read loop
+// forwards to the dispatch loop until the client closes
+func newTestClient(t *testing.T) (*Client, chan struct{}) {
+	_, client, chans, err := NewClient(context.Background(), "testModule", nil, map[string]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drain the answers until the client closes its outgoing channel
+	drained := make(chan struct{})
+	go func() {
+		for range chans.Outgoing {
+		}
+		close(drained)
+	}()
+
+	for i := 0; i < 1000; i++ {
+		chans.Incoming <- JsonRPCMessage{Version: common.Vsn, Method: "testModule_notify", Params: json.RawMessage(`[]`)}
+	}
+	return client, drained
+}
+
+// closeClients calls Close on the client from several goroutines and fails if they do not all return
+func closeClients(t *testing.T, client *Client, callers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Close()
+		}()
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return while messages were arriving")
+	}
+}
+
+// TestReadLeavesCloseSignal feeds messages through the read loop while signalling close. Only the dispatch loop
+// may take the signal, if the read loop took it Close would wait forever for the client to close.
+func TestReadLeavesCloseSignal(t *testing.T) {
+	c := &Client{
+		close:  make(chan struct{}),
+		closed: make(chan struct{}),
+		readOp: make(chan readOp),
+	}
+	incoming := make(chan JsonRPCMessage)
+	readDone := make(chan struct{})
+	go func() {
+		c.read(incoming)
+		close(readDone)
+	}()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			case incoming <- JsonRPCMessage{Version: common.Vsn, Method: "testModule_notify"}:
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-c.readOp:
+			}
+		}
+	}()
+
+	select {
+	case c.close <- struct{}{}:
+		t.Error("Expected the read loop to leave the close signal to the dispatch loop")
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(stop)
+	wg.Wait()
+
+	close(c.closed)
+	select {
+	case <-readDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the read loop to return once the client is closed")
+	}
+}
+
+// TestCloseWhileMessagesArrive closes clients with incoming messages queued for the read loop
+func TestCloseWhileMessagesArrive(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		client, drained := newTestClient(t)
+		closeClients(t, client, 1)
+		<-drained
+	}
+}
+
+// TestConcurrentClose closes clients from several goroutines at once, every call must return without
+// sending on a channel the dispatch loop closed
+func TestConcurrentClose(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		client, drained := newTestClient(t)
+		closeClients(t, client, 3)
+		<-drained
+		client.Close()
+	}
+}
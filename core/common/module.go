@@ -134,6 +134,47 @@ func (r *ModuleRegistry) StopModuleServices() (stopped []string, err error) {
 	return stopped, err
 }
 
+// DrainModuleServices drains all running services that implement Drainer concurrently,
+// returning once all of them are drained or the context is done
+func (r *ModuleRegistry) DrainModuleServices(ctx context.Context) (drained []string, err error) {
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures = make(map[string]error)
+	)
+
+	for _, module := range r.Modules() {
+		if !module.ServiceAlive {
+			continue
+		}
+		drainer, ok := module.Service.(Drainer)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, drainer Drainer) {
+			defer wg.Done()
+			err := drainer.Drain(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures[name] = err
+			} else {
+				drained = append(drained, name)
+			}
+		}(module.Name, drainer)
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		err = fmt.Errorf("failed to drain modules: %v", failures)
+	}
+
+	return drained, err
+}
+
 func (r *ModuleRegistry) stopModuleService(moduleName string) error {
 
 	r.mu.Lock()
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/pon-network/mev-plus/common"
@@ -18,6 +19,12 @@ type Service interface {
 	CliCommand() *cli.Command // Returns the cli command for the service in order for MEV Plus to parse the flags
 }
 
+// Drainer is optionally implemented by a service that has in-flight work to finish before it is stopped.
+// The core drains all services before stopping any of them, so calls between modules still work while draining.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
 // Should not be accessible over communication channels
 var ParkedCallbacks map[string]bool = map[string]bool{
 	"start":       true,
@@ -25,6 +32,7 @@ var ParkedCallbacks map[string]bool = map[string]bool{
 	"connectCore": true,
 	"configure":   true,
 	"cliCommand":  true,
+	"drain":       true,
 }
 
 type Module struct {
@@ -7,6 +7,7 @@ import (
 
 	aggregator "github.com/pon-network/mev-plus/modules/block-aggregator"
 	builderApi "github.com/pon-network/mev-plus/modules/builder-api"
+	chainClock "github.com/pon-network/mev-plus/modules/chain-clock"
 	proxyModule "github.com/pon-network/mev-plus/modules/external-validator-proxy"
 	relay "github.com/pon-network/mev-plus/modules/relay"
 
@@ -25,6 +26,7 @@ func init() {
 		relay.NewRelayService(),
 		aggregator.NewBlockAggregatorService(),
 		proxyModule.NewExternalValidatorProxyService(),
+		chainClock.NewChainClockService(),
 	}
 
 	AdditionalFunctionalities = []*cli.Command{
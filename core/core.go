@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/pon-network/mev-plus/common"
 	coreCommon "github.com/pon-network/mev-plus/core/common"
@@ -22,6 +23,10 @@ const (
 	closedState
 )
 
+// moduleDrainTimeout bounds how long the core waits for modules to drain on close,
+// modules apply their own, usually shorter, deadlines within it
+const moduleDrainTimeout = 30 * time.Second
+
 // CoreService represents the ccore service that handles events and notifies attached services.
 type CoreService struct {
 	moduleRegistry  coreCommon.ModuleRegistry
@@ -242,6 +247,13 @@ func (c *CoreService) Close() error {
 
 func (c *CoreService) close() error {
 
+	// Let modules finish in-flight work while communication between them is still up
+	drainCtx, cancel := context.WithTimeout(context.Background(), moduleDrainTimeout)
+	defer cancel()
+	if _, err := c.moduleRegistry.DrainModuleServices(drainCtx); err != nil {
+		log.WithError(err).Warn("Modules did not drain cleanly")
+	}
+
 	// After all module clients have been closed, close the core client
 	if c.coreClient != nil {
 		c.coreClient.Close()
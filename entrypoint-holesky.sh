@@ -27,6 +27,7 @@ esac
 
 ./mevPlus \
    -builderApi.listen-address http://0.0.0.0:18551 \
+   -builderApi.network holesky \
    -k2.eth1-private-key $ETH1_PRIVATE_KEY \
    -k2.beacon-node-url $BEACON_NODE_API \
    -k2.execution-node-url $EXECUTION_LAYER \
@@ -0,0 +1,308 @@
+package blockaggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/proposer"
+	"github.com/sirupsen/logrus"
+)
+
+var errNoAuctionRunning = errors.New("no auction running for the slot, parent hash and proposer")
+
+// auction collects the bids of block sources for a getHeader request, from polling the
+// block sources and from bids they submit, until the auction ends
+type auction struct {
+	options   proposer.Options
+	threshold *big.Int
+
+	mu     sync.Mutex
+	bids   []sourceBid
+	closed bool
+
+	thresholdReached chan struct{}
+	thresholdOnce    sync.Once
+}
+
+func newAuction(options proposer.Options, threshold *big.Int) *auction {
+	return &auction{
+		options:          options,
+		threshold:        threshold,
+		thresholdReached: make(chan struct{}),
+	}
+}
+
+// submit adds a bid to the auction, a bid for a block hash already in the auction replaces it
+func (a *auction) submit(bid sourceBid) error {
+	if !a.options.AllowsBlockSource(bid.module) {
+		return fmt.Errorf("block source %s is not used for the proposer", bid.module)
+	}
+
+	blockHash, err := bid.response.BlockHash()
+	if err != nil {
+		return err
+	}
+	value, err := bid.response.Value()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return errNoAuctionRunning
+	}
+	bid.receivedAt = time.Now()
+
+	replaced := false
+	for i, existing := range a.bids {
+		if existingHash, err := existing.response.BlockHash(); err == nil && existingHash == blockHash {
+			a.bids[i] = bid
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		a.bids = append(a.bids, bid)
+	}
+
+	minBid := a.options.MinBidValue()
+	if a.threshold != nil && value.ToBig().Cmp(a.threshold) >= 0 && (minBid == nil || value.ToBig().Cmp(minBid) >= 0) {
+		a.thresholdOnce.Do(func() { close(a.thresholdReached) })
+	}
+
+	return nil
+}
+
+// close ends the auction and returns the bids it collected
+func (a *auction) close() []sourceBid {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+	return a.bids
+}
+
+// runAuction queries the block sources as soon as the request arrives and collects bids until the auction deadline,
+// re-polling the block sources if a poll interval is set. The auction ends early once a bid reaches the value
+// threshold, and never runs past its hard deadline.
+func (b *BlockAggregatorService) runAuction(slot uint64, parentHash, proposerPubkey string, options proposer.Options, blockSources []string) []sourceBid {
+	key := newHeaderRequestKey(slot, parentHash, proposerPubkey)
+	a := newAuction(options, b.cfg.AuctionValueThreshold)
+
+	b.auctionsLock.Lock()
+	b.auctions[key] = a
+	b.auctionsLock.Unlock()
+	defer func() {
+		b.auctionsLock.Lock()
+		delete(b.auctions, key)
+		b.auctionsLock.Unlock()
+	}()
+
+	// Without the chain clock the slot start is unknown and the block sources are polled once
+	deadline := time.Now()
+	if slotStart, ok := b.slotStart(slot); ok {
+		deadline = slotStart.Add(time.Duration(b.cfg.AuctionDuration) * time.Second)
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if hardDeadline, ok := b.auctionHardDeadline(slot, deadline); ok {
+		ctx, cancel = context.WithDeadline(context.Background(), hardDeadline)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	// Cancelling also stops any polling still running once the auction ends
+	defer cancel()
+
+	firstPollDone := make(chan struct{})
+	go func() {
+		b.pollBlockSources(ctx, a, slot, parentHash, proposerPubkey, blockSources)
+		close(firstPollDone)
+
+		if b.cfg.AuctionPollInterval == 0 {
+			return
+		}
+		interval := time.Duration(b.cfg.AuctionPollInterval) * time.Millisecond
+		for time.Now().Add(interval).Before(deadline) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			b.pollBlockSources(ctx, a, slot, parentHash, proposerPubkey, blockSources)
+		}
+	}()
+
+	// The auction lasts until the deadline, but no less than one round of responses from the block sources
+	select {
+	case <-firstPollDone:
+		select {
+		case <-time.After(time.Until(deadline)):
+		case <-a.thresholdReached:
+			b.log.WithField("slot", slot).Debug("auction ended early, bid reached the value threshold")
+		case <-ctx.Done():
+			b.log.WithField("slot", slot).Warn("auction ended at its hard deadline")
+		}
+	case <-a.thresholdReached:
+		b.log.WithField("slot", slot).Debug("auction ended early, bid reached the value threshold")
+	case <-ctx.Done():
+		b.log.WithField("slot", slot).Warn("auction ended at its hard deadline")
+	}
+
+	return a.close()
+}
+
+// pollBlockSources requests a header from each block source and adds the returned bids to the auction
+func (b *BlockAggregatorService) pollBlockSources(ctx context.Context, a *auction, slot uint64, parentHash, proposerPubkey string, blockSources []string) {
+	var wg sync.WaitGroup
+
+	handleModule := func(module string) {
+		defer wg.Done()
+
+		// A slow block source is cut short at its timeout instead of holding up the auction
+		callCtx := ctx
+		if timeout := b.sourceTimeout(module); timeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		var result []spec.VersionedSignedBuilderBid
+		start := time.Now()
+		err := b.coreClient.CallContext(callCtx, &result, module+"_getHeader", false, nil, slot, parentHash, proposerPubkey) // No need to notify modules on each handler since notified all modules once already
+		latency := time.Since(start)
+		if ctx.Err() != nil {
+			// The auction ended before the block source responded, which is not the block source failing
+			b.log.WithFields(logrus.Fields{
+				"module":  module,
+				"latency": latency.String(),
+			}).Warn("block source did not respond before the auction ended, dropping its response")
+			return
+		}
+		if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			b.log.WithFields(logrus.Fields{
+				"module":  module,
+				"latency": latency.String(),
+			}).Warn("block source exceeded its timeout, dropping its response")
+		}
+		if b.circuitBreakers.record(module, latency, err) {
+			b.log.WithError(err).WithField("module", module).Warn("block source failing, circuit breaker open, skipping it on getHeader")
+		}
+		if err != nil && !isNoContentError(err) {
+			b.log.WithError(err).WithField("module", module).Warn("error calling module")
+			return
+		}
+
+		if len(result) == 0 {
+			b.log.WithField("module", module).Warn("module returned no header response")
+			return
+		}
+
+		for _, header := range result {
+			if header.IsEmpty() {
+				b.log.WithField("module", module).Warn("module returned empty header response")
+				continue
+			}
+
+			if err := a.submit(sourceBid{module: module, response: header}); err != nil {
+				b.log.WithError(err).WithFields(logrus.Fields{
+					"module":  module,
+					"latency": latency.String(),
+				}).Debug("header response not added to the auction")
+				continue
+			}
+			b.log.WithField("module", module).Debug("module returned header response")
+		}
+	}
+
+	for _, module := range blockSources {
+		allowed, probe := b.circuitBreakers.allow(module)
+		if probe {
+			go b.probeBlockSource(module)
+		}
+		if !allowed {
+			b.log.WithField("module", module).Debug("skipping block source, circuit breaker open")
+			continue
+		}
+
+		wg.Add(1)
+		go handleModule(module)
+	}
+
+	wg.Wait()
+}
+
+// sourceTimeout returns the timeout of getHeader calls to the block source, 0 for no timeout
+func (b *BlockAggregatorService) sourceTimeout(module string) time.Duration {
+	timeout, ok := b.cfg.SourceTimeouts[module]
+	if !ok {
+		timeout = b.cfg.SourceTimeout
+	}
+	return time.Duration(timeout) * time.Millisecond
+}
+
+// auctionHardDeadline returns the time by which the auction must end so getHeader answers within the beacon node's
+// budget. It is the latency budget from the slot start if one is set, otherwise one block source timeout after the
+// auction deadline.
+func (b *BlockAggregatorService) auctionHardDeadline(slot uint64, deadline time.Time) (time.Time, bool) {
+	if b.cfg.AuctionMaxLatency > 0 {
+		if slotStart, ok := b.slotStart(slot); ok {
+			return slotStart.Add(time.Duration(b.cfg.AuctionMaxLatency) * time.Millisecond), true
+		}
+	}
+
+	if b.cfg.SourceTimeout == 0 {
+		return time.Time{}, false
+	}
+	if now := time.Now(); deadline.Before(now) {
+		deadline = now
+	}
+	return deadline.Add(time.Duration(b.cfg.SourceTimeout) * time.Millisecond), true
+}
+
+// SubmitBid adds a bid from a connected block source to the running auction for the slot, parent hash and proposer.
+// Block sources can use it to stream bids while an auction runs instead of waiting to be polled.
+func (b *BlockAggregatorService) SubmitBid(moduleName string, slot uint64, parentHash, proposerPubkey string, bid spec.VersionedSignedBuilderBid) error {
+	if bid.IsEmpty() {
+		return &common.InvalidParamsError{Message: "empty bid"}
+	}
+
+	b.lock.Lock()
+	connected := false
+	for _, module := range b.ConnectedBLockSources {
+		if module == moduleName {
+			connected = true
+			break
+		}
+	}
+	b.lock.Unlock()
+	if !connected {
+		return fmt.Errorf("[%v] is not a connected block source", moduleName)
+	}
+
+	b.auctionsLock.Lock()
+	a, ok := b.auctions[newHeaderRequestKey(slot, parentHash, proposerPubkey)]
+	b.auctionsLock.Unlock()
+	if !ok {
+		return errNoAuctionRunning
+	}
+
+	if err := a.submit(sourceBid{module: moduleName, response: bid}); err != nil {
+		return err
+	}
+
+	b.log.WithFields(logrus.Fields{
+		"module": moduleName,
+		"slot":   slot,
+	}).Debug("block source submitted bid")
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package blockaggregator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-builder-client/api/capella"
+	"github.com/attestantio/go-builder-client/spec"
+	consensusspec "github.com/attestantio/go-eth2-client/spec"
+	capella2 "github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/holiman/uint256"
+	"github.com/pon-network/mev-plus/common/proposer"
+)
+
+func testAuctionBid(blockHash byte, value uint64) spec.VersionedSignedBuilderBid {
+	header := &capella2.ExecutionPayloadHeader{}
+	header.BlockHash[0] = blockHash
+	return spec.VersionedSignedBuilderBid{
+		Version: consensusspec.DataVersionCapella,
+		Capella: &capella.SignedBuilderBid{
+			Message: &capella.BuilderBid{Value: uint256.NewInt(value), Header: header},
+		},
+	}
+}
+
+func TestAuctionSubmit(t *testing.T) {
+	a := newAuction(proposer.Options{BlockSources: []string{"relay"}}, big.NewInt(100))
+
+	if err := a.submit(sourceBid{module: "builder", response: testAuctionBid(1, 10)}); err == nil {
+		t.Error("Expected a bid from a block source not used for the proposer to be refused")
+	}
+
+	if err := a.submit(sourceBid{module: "relay", response: testAuctionBid(1, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	// The same block from a later poll replaces the earlier bid
+	if err := a.submit(sourceBid{module: "relay", response: testAuctionBid(1, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-a.thresholdReached:
+		t.Fatal("Threshold should not be reached by a bid below it")
+	default:
+	}
+
+	if err := a.submit(sourceBid{module: "relay", response: testAuctionBid(2, 100)}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-a.thresholdReached:
+	default:
+		t.Fatal("Expected the threshold to be reached")
+	}
+
+	if bids := a.close(); len(bids) != 2 {
+		t.Errorf("Expected 2 distinct bids, got %d", len(bids))
+	}
+	if err := a.submit(sourceBid{module: "relay", response: testAuctionBid(3, 100)}); !errors.Is(err, errNoAuctionRunning) {
+		t.Errorf("Expected bids after the auction ends to be refused, got %v", err)
+	}
+}
+
+func TestRunAuction(t *testing.T) {
+	newService := func() *BlockAggregatorService {
+		b := NewBlockAggregatorService()
+		b.ConnectedBLockSources = []string{"builder"}
+		genesis := time.Now()
+		b.slotStart = func(slot uint64) (time.Time, bool) { return genesis.Add(time.Duration(slot) * 12 * time.Second), true }
+		b.cfg.AuctionDuration = 10
+		return b
+	}
+
+	t.Run("EndsAtValueThreshold", func(t *testing.T) {
+		b := newService()
+		b.cfg.AuctionValueThreshold = big.NewInt(50)
+
+		go func() {
+			for {
+				err := b.SubmitBid("builder", 0, testParentHash, testPubkey, testAuctionBid(1, 60))
+				if !errors.Is(err, errNoAuctionRunning) {
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+
+		start := time.Now()
+		bids := b.runAuction(0, testParentHash, testPubkey, proposer.Options{}, nil)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the auction to end at the value threshold, took %s", elapsed)
+		}
+		if len(bids) != 1 || bids[0].module != "builder" {
+			t.Errorf("Expected the submitted bid, got %+v", bids)
+		}
+	})
+
+	t.Run("EndsAtLatencyBudget", func(t *testing.T) {
+		b := newService()
+		slotStart, _ := b.slotStart(0)
+		b.cfg.AuctionMaxLatency = uint64(time.Since(slotStart).Milliseconds()) + 200
+
+		start := time.Now()
+		b.runAuction(0, testParentHash, testPubkey, proposer.Options{}, nil)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the auction to end at the latency budget, took %s", elapsed)
+		}
+	})
+
+	t.Run("SubmitWithoutAuction", func(t *testing.T) {
+		b := newService()
+		if err := b.SubmitBid("builder", 0, testParentHash, testPubkey, testAuctionBid(1, 60)); !errors.Is(err, errNoAuctionRunning) {
+			t.Errorf("Expected no auction running, got %v", err)
+		}
+		if err := b.SubmitBid("unknown", 0, testParentHash, testPubkey, testAuctionBid(1, 60)); err == nil {
+			t.Error("Expected a bid from a module that is not a block source to be refused")
+		}
+	})
+}
+
+func TestSourceTimeouts(t *testing.T) {
+	b := NewBlockAggregatorService()
+
+	sourceTimeouts, err := parseSourceTimeouts("relay=300, externalValidatorProxy=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.cfg.SourceTimeout = 950
+	b.cfg.SourceTimeouts = sourceTimeouts
+	if timeout := b.sourceTimeout("relay"); timeout != 300*time.Millisecond {
+		t.Errorf("Expected the per source timeout, got %s", timeout)
+	}
+	if timeout := b.sourceTimeout("externalValidatorProxy"); timeout != 0 {
+		t.Errorf("Expected no timeout for the block source, got %s", timeout)
+	}
+	if timeout := b.sourceTimeout("builder"); timeout != 950*time.Millisecond {
+		t.Errorf("Expected the global timeout, got %s", timeout)
+	}
+
+	for _, invalid := range []string{"relay", "relay=fast", "=100"} {
+		if _, err := parseSourceTimeouts(invalid); err == nil {
+			t.Errorf("Expected an error parsing %q", invalid)
+		}
+	}
+
+	// Without a latency budget the auction ends one source timeout after its deadline
+	deadline := time.Now().Add(time.Second)
+	if hardDeadline, ok := b.auctionHardDeadline(1, deadline); !ok || !hardDeadline.Equal(deadline.Add(950*time.Millisecond)) {
+		t.Errorf("Unexpected hard deadline %s", hardDeadline)
+	}
+	if hardDeadline, _ := b.auctionHardDeadline(1, time.Unix(0, 0)); time.Until(hardDeadline) > 950*time.Millisecond {
+		t.Errorf("Expected a passed auction deadline to end one source timeout from now, got %s", hardDeadline)
+	}
+
+	// The latency budget needs the slot start from the chain clock
+	b.cfg.AuctionMaxLatency = 1500
+	if hardDeadline, _ := b.auctionHardDeadline(0, deadline); !hardDeadline.Equal(deadline.Add(950 * time.Millisecond)) {
+		t.Errorf("Expected the source timeout without the chain clock, got %s", hardDeadline)
+	}
+	slotStart := time.Now()
+	b.slotStart = func(uint64) (time.Time, bool) { return slotStart, true }
+	if hardDeadline, _ := b.auctionHardDeadline(0, deadline); !hardDeadline.Equal(slotStart.Add(1500 * time.Millisecond)) {
+		t.Errorf("Expected the latency budget from the slot start, got %s", hardDeadline)
+	}
+}
@@ -2,17 +2,20 @@ package blockaggregator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	apiv1 "github.com/attestantio/go-builder-client/api/v1"
-	"github.com/attestantio/go-builder-client/spec"
+	"github.com/pon-network/mev-plus/common"
 	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
 
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
 )
 
+var errBlindedBlockSubmissionUnsupported = errors.New("block source does not support blinded block submission without returning the payload")
+
 // This is used by builder api to check the status of any block producers or relays
 func (b *BlockAggregatorService) checkBlockSources() error {
 
@@ -67,13 +70,22 @@ func (b *BlockAggregatorService) processValidatorRegistrations(payload []apiv1.S
 	var errors []error
 	var successfulRegistrations []string
 
+	for i, registration := range payload {
+		if registration.Message == nil {
+			return &common.InvalidParamsError{Message: fmt.Sprintf("validator registration %d has no message", i)}
+		}
+	}
+
+	// Bids for the proposers are checked against their latest registration
+	b.registrations.store(payload)
+
 	// Notify all modules of the new validator registrations once
 	_ = b.coreClient.Notify(context.Background(), "core_registerValidator", true, append(b.ConnectedBLockSources, b.ModuleNotificationExclusions...), payload)
 
-	handleRegistration := func(module string) {
+	handleRegistration := func(module string, registrations []apiv1.SignedValidatorRegistration) {
 
 		defer wg.Done()
-		err := b.coreClient.Call(nil, module+"_registerValidator", false, nil, payload) // No need to notify modules on each handler since notified all modules once already
+		err := b.coreClient.Call(nil, module+"_registerValidator", false, nil, registrations) // No need to notify modules on each handler since notified all modules once already
 		if err != nil {
 			b.log.WithError(err).WithField("module", module).Warn("error calling module")
 			mu.Lock()
@@ -81,13 +93,26 @@ func (b *BlockAggregatorService) processValidatorRegistrations(payload []apiv1.S
 			errors = append(errors, err)
 			return
 		}
+		mu.Lock()
 		successfulRegistrations = append(successfulRegistrations, module)
+		mu.Unlock()
 		b.log.WithField("module", module).Infof("Successfully registered validator with connected block source: %s", module)
 	}
 
 	for _, module := range b.ConnectedBLockSources {
+		// Only register the validators that the proposer config assigns to the block source
+		var registrations []apiv1.SignedValidatorRegistration
+		for _, registration := range payload {
+			if b.proposerConfig.For(registration.Message.Pubkey.String()).AllowsBlockSource(module) {
+				registrations = append(registrations, registration)
+			}
+		}
+		if len(registrations) == 0 {
+			continue
+		}
+
 		wg.Add(1)
-		go handleRegistration(module)
+		go handleRegistration(module, registrations)
 	}
 
 	wg.Wait()
@@ -101,71 +126,34 @@ func (b *BlockAggregatorService) processValidatorRegistrations(payload []apiv1.S
 
 func (b *BlockAggregatorService) processHeaderReq(slot uint64, parentHash, proposerPubkey string) (data.SlotHeader, error) {
 
-	slotTime := b.cfg.GenesisTime + (slot * b.cfg.SlotDuration)
-	auctionDeadline := slotTime + b.cfg.AuctionDuration
-
-	// Calculate the time remaining until the auction deadline
-	timeUntilDeadline := time.Until(time.Unix(int64(auctionDeadline), 0))
-	if timeUntilDeadline > 0 {
-		// Sleep until the auction deadline has passed
-		time.Sleep(timeUntilDeadline)
-	}
-
 	// Notify all modules of the new slot header request once
 	_ = b.coreClient.Notify(context.Background(), "core_getHeader", true, append(b.ConnectedBLockSources, b.ModuleNotificationExclusions...), slot, parentHash, proposerPubkey)
 
-	var wg sync.WaitGroup
-	type resultData struct {
-		module   string
-		response spec.VersionedSignedBuilderBid
-	}
-	resultChan := make(chan resultData, len(b.ConnectedBLockSources))
-
-	handleModule := func(module string) {
-		defer wg.Done()
-
-		var result []spec.VersionedSignedBuilderBid
-		err := b.coreClient.Call(&result, module+"_getHeader", false, nil, slot, parentHash, proposerPubkey) // No need to notify modules on each handler since notified all modules once already
-		if err != nil {
-			b.log.WithError(err).WithField("module", module).Warn("error calling module")
-			return
-		}
-
-		if len(result) == 0 {
-			b.log.WithField("module", module).Warn("module returned no header response")
-			return
-		}
-
-		for _, header := range result {
-			if header.IsEmpty() {
-				b.log.WithField("module", module).Warn("module returned empty header response")
-				continue
-			}
-
-			b.log.WithField("module", module).Info("module returned header response")
-			resultChan <- resultData{module, header}
-		}
-	}
-
+	options := b.proposerConfig.For(proposerPubkey)
+	var blockSources []string
 	for _, module := range b.ConnectedBLockSources {
-		wg.Add(1)
-		go handleModule(module)
+		if options.AllowsBlockSource(module) {
+			blockSources = append(blockSources, module)
+		}
 	}
 
-	wg.Wait()
-	close(resultChan)
+	results := b.runAuction(slot, parentHash, proposerPubkey, options, blockSources)
 
-	for result := range resultChan {
-		err := b.processNewBid(result.module, slot, result.response)
+	for _, result := range b.selectBids(results, options) {
+		err := b.processNewBid(result.module, slot, proposerPubkey, result.response, options)
 		if err != nil {
 			return data.SlotHeader{}, err
 		}
 	}
 
-	slotHeader, err := b.Data.GetSelectedSlotHeaders(slot)
+	slotHeader, err := b.Data.GetSelectedSlotHeader(slot, parentHash, proposerPubkey)
 	if err != nil {
-		return data.SlotHeader{}, err
+		go b.recordAuction(slot, parentHash, proposerPubkey, results, nil)
+		return data.SlotHeader{}, &common.NoContentError{Message: err.Error()}
 	}
+	go b.recordAuction(slot, parentHash, proposerPubkey, results, &slotHeader)
+
+	b.reputations.headerWon(slotHeader.ModuleName)
 
 	// Notify modules on receipt of new slot header
 	_ = b.coreClient.Notify(context.Background(), "core_receivedHeader", true, append(b.ConnectedBLockSources, b.ModuleNotificationExclusions...), *slotHeader.Bid)
@@ -175,23 +163,25 @@ func (b *BlockAggregatorService) processHeaderReq(slot uint64, parentHash, propo
 
 func (b *BlockAggregatorService) processPayloadReq(VersionedSignedBlindedBeaconBlock commonTypes.VersionedSignedBlindedBeaconBlock) (versionedExecutionPayload []commonTypes.VersionedExecutionPayloadV2WithVersionName, slotHeader data.SlotHeader, err error) {
 
-	baseSignedBlindedBeaconBlock, err := VersionedSignedBlindedBeaconBlock.ToBaseSignedBlindedBeaconBlock()
+	slotHeader, err = b.blockSlotHeader(VersionedSignedBlindedBeaconBlock)
 	if err != nil {
 		return versionedExecutionPayload, slotHeader, err
 	}
 
-	slotHeader, err = b.Data.GetSlotHeaderByHash(baseSignedBlindedBeaconBlock.Message.Body.ExecutionPayloadHeader.BlockHash.String())
-	if err != nil {
+	if err = b.recordBlindedBlock(VersionedSignedBlindedBeaconBlock); err != nil {
 		return versionedExecutionPayload, slotHeader, err
 	}
 
-	if slotHeader.Bid.IsEmpty() {
-		return versionedExecutionPayload, slotHeader, fmt.Errorf("slot header bid is empty")
-	}
-
 	var result []commonTypes.VersionedExecutionPayloadV2WithVersionName
 	b.log.WithField("fromModule", slotHeader.ModuleName).Info("Getting payload from block source")
+	start := time.Now()
 	err = b.coreClient.Call(&result, slotHeader.ModuleName+"_getPayload", true, append(b.ConnectedBLockSources, b.ModuleNotificationExclusions...), &VersionedSignedBlindedBeaconBlock) // Since the call is made once and not a looped handler, can notify all modules once while executing the call
+	if err == nil && len(result) == 0 {
+		err = fmt.Errorf("block source returned no payload")
+	}
+	// A block source that wins the auction and withholds the payload costs the whole block
+	b.reputations.payloadResult(slotHeader.ModuleName, time.Since(start), err)
+	go b.recordDelivery(slotHeader, start, time.Since(start), false, err)
 	if err != nil {
 		return versionedExecutionPayload, slotHeader, err
 	}
@@ -204,3 +194,65 @@ func (b *BlockAggregatorService) processPayloadReq(VersionedSignedBlindedBeaconB
 	return result, slotHeader, nil
 
 }
+
+func (b *BlockAggregatorService) processBlindedBlockSubmission(VersionedSignedBlindedBeaconBlock commonTypes.VersionedSignedBlindedBeaconBlock) (slotHeader data.SlotHeader, err error) {
+
+	slotHeader, err = b.blockSlotHeader(VersionedSignedBlindedBeaconBlock)
+	if err != nil {
+		return slotHeader, err
+	}
+
+	// Only block sources that publish blocks themselves can accept a block without returning its payload
+	method := slotHeader.ModuleName + "_submitBlindedBlock"
+	if !b.coreClient.HasCallback(method) {
+		return slotHeader, fmt.Errorf("%w: %s", errBlindedBlockSubmissionUnsupported, slotHeader.ModuleName)
+	}
+
+	if err = b.recordBlindedBlock(VersionedSignedBlindedBeaconBlock); err != nil {
+		return slotHeader, err
+	}
+
+	b.log.WithField("fromModule", slotHeader.ModuleName).Info("Submitting blinded block to block source for publication")
+	start := time.Now()
+	err = b.coreClient.Call(nil, method, true, append(b.ConnectedBLockSources, b.ModuleNotificationExclusions...), &VersionedSignedBlindedBeaconBlock)
+	// The block source confirming the block will be published counts as delivering its payload
+	b.reputations.payloadResult(slotHeader.ModuleName, time.Since(start), err)
+	go b.recordDelivery(slotHeader, start, time.Since(start), true, err)
+	if err != nil {
+		return slotHeader, err
+	}
+
+	// Notify modules of the block submitted for publication
+	_ = b.coreClient.Notify(context.Background(), "core_submittedBlindedBlock", true, append(b.ConnectedBLockSources, b.ModuleNotificationExclusions...), VersionedSignedBlindedBeaconBlock)
+
+	return slotHeader, nil
+}
+
+// blockSlotHeader returns the selected slot header the blinded block was built on
+func (b *BlockAggregatorService) blockSlotHeader(VersionedSignedBlindedBeaconBlock commonTypes.VersionedSignedBlindedBeaconBlock) (slotHeader data.SlotHeader, err error) {
+
+	baseSignedBlindedBeaconBlock, err := VersionedSignedBlindedBeaconBlock.ToBaseSignedBlindedBeaconBlock()
+	if err != nil {
+		return slotHeader, err
+	}
+
+	slotHeader, err = b.Data.GetSlotHeaderByHash(baseSignedBlindedBeaconBlock.Message.Body.ExecutionPayloadHeader.BlockHash.String())
+	if err != nil {
+		return slotHeader, &common.InvalidParamsError{Message: err.Error()}
+	}
+
+	if slotHeader.Bid.IsEmpty() {
+		return slotHeader, fmt.Errorf("slot header bid is empty")
+	}
+
+	return slotHeader, nil
+}
+
+// recordBlindedBlock records the block before its payload can be revealed, refusing a second block for the same slot and proposer
+func (b *BlockAggregatorService) recordBlindedBlock(VersionedSignedBlindedBeaconBlock commonTypes.VersionedSignedBlindedBeaconBlock) error {
+	baseSignedBlindedBeaconBlock, err := VersionedSignedBlindedBeaconBlock.ToBaseSignedBlindedBeaconBlock()
+	if err != nil {
+		return err
+	}
+	return b.checkEquivocation(VersionedSignedBlindedBeaconBlock, baseSignedBlindedBeaconBlock.Message.Slot, baseSignedBlindedBeaconBlock.Message.ProposerIndex, baseSignedBlindedBeaconBlock.Message.Body.ExecutionPayloadHeader.BlockHash.String())
+}
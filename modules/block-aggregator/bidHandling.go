@@ -1,29 +1,121 @@
 package blockaggregator
 
 import (
+	"strings"
+	"time"
+
 	"github.com/attestantio/go-builder-client/spec"
+	"github.com/pon-network/mev-plus/common/proposer"
 	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
+	"github.com/sirupsen/logrus"
 )
 
-func (b *BlockAggregatorService) processNewBid(name string, slot uint64, bid spec.VersionedSignedBuilderBid) error {
+// sourceBid is a bid returned by a block source module
+type sourceBid struct {
+	module     string
+	response   spec.VersionedSignedBuilderBid
+	receivedAt time.Time
+}
+
+// selectBids drops the bids below the proposer's min bid and, for the source priority
+// strategy, keeps only the bids of the most preferred block source that returned one
+func (b *BlockAggregatorService) selectBids(bids []sourceBid, options proposer.Options) []sourceBid {
+	minBid := options.MinBidValue()
+
+	var selected []sourceBid
+	bestPriority := -1
+	for _, bid := range bids {
+		if minBid != nil {
+			value, err := bid.response.Value()
+			if err != nil || value.ToBig().Cmp(minBid) < 0 {
+				b.log.WithField("module", bid.module).Debug("ignoring bid below the proposer min bid")
+				continue
+			}
+		}
+
+		if !b.reputationAllows(bid.module) {
+			b.log.WithField("module", bid.module).Debug("ignoring bid from block source with a reputation below the minimum score")
+			continue
+		}
+
+		if options.SelectionStrategy() == proposer.SelectionSourcePriority {
+			priority := options.BlockSourcePriority(bid.module)
+			if bestPriority >= 0 && priority > bestPriority {
+				continue
+			}
+			if priority < bestPriority {
+				selected = nil
+			}
+			bestPriority = priority
+		}
+
+		selected = append(selected, bid)
+	}
+
+	return selected
+}
+
+func (b *BlockAggregatorService) processNewBid(name string, slot uint64, proposerPubkey string, bid spec.VersionedSignedBuilderBid, options proposer.Options) error {
+
+	if err := b.checkForkVersion(slot, bid.Version); err != nil {
+		rejectedForkBids.Add(name, 1)
+		b.log.WithError(err).WithFields(logrus.Fields{
+			"module": name,
+			"slot":   slot,
+		}).Warn("rejected bid not of the fork of the slot")
+		return nil
+	}
 
 	value, err := bid.Value()
 	if err != nil {
 		return err
 	}
 
+	builder, err := bid.Builder()
+	if err != nil {
+		return err
+	}
+	if reason := b.builderRejection(builder.String(), options); reason != "" {
+		rejectedBuilderBids.Add(builder.String(), 1)
+		b.log.WithFields(logrus.Fields{
+			"module":  name,
+			"slot":    slot,
+			"builder": builder.String(),
+			"reason":  reason,
+		}).Info("rejected bid from builder")
+		return nil
+	}
+
+	if err := b.checkRegistration(proposerPubkey, bid); err != nil {
+		registrationViolations.Add(name, 1)
+		b.log.WithError(err).WithFields(logrus.Fields{
+			"module":   name,
+			"slot":     slot,
+			"builder":  builder.String(),
+			"proposer": proposerPubkey,
+		}).Warn("rejected bid not matching the proposer registration")
+		return nil
+	}
+
 	blockHash, err := bid.BlockHash()
 	if err != nil {
 		return err
 	}
+	parentHash, err := bid.ParentHash()
+	if err != nil {
+		return err
+	}
 
 	processedHeader := data.SlotHeader{
-		ModuleName: name,
-		Slot:       slot,
-		Bid:        &bid,
-		Value:      value.ToBig(),
-		BlockHash:  blockHash.String(),
+		ModuleName:     name,
+		Slot:           slot,
+		Bid:            &bid,
+		Value:          value.ToBig(),
+		BlockHash:      blockHash.String(),
+		ParentHash:     strings.ToLower(parentHash.String()),
+		ProposerPubkey: strings.ToLower(proposerPubkey),
 	}
+	processedHeader.SelectionValue = b.reputationSelectionValue(name, processedHeader.Value)
 
 	err = b.Data.AddSlotHeader(processedHeader)
 	if err != nil {
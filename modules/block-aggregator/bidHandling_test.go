@@ -1,6 +1,8 @@
 package blockaggregator
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/attestantio/go-builder-client/api/capella"
@@ -8,6 +10,10 @@ import (
 	consensusspec "github.com/attestantio/go-eth2-client/spec"
 	capella2 "github.com/attestantio/go-eth2-client/spec/capella"
 	"github.com/holiman/uint256"
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
+	"github.com/pon-network/mev-plus/common/proposer"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/config"
 	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
 )
 
@@ -30,7 +36,7 @@ func TestProcessNewBid(t *testing.T) {
 			},
 		}
 
-		err := b.processNewBid("NewBid", 12, *bid)
+		err := b.processNewBid("NewBid", 12, testPubkey, *bid, proposer.Options{})
 		if err != nil {
 			t.Errorf("Error in processing new bid %v", err)
 		}
@@ -55,7 +61,7 @@ func TestProcessNewBid(t *testing.T) {
 		}
 		// Set the last slot to 13
 		aggregator.SetLastSlot(13)
-		err := b.processNewBid("NewBid", 12, *bid)
+		err := b.processNewBid("NewBid", 12, testPubkey, *bid, proposer.Options{})
 		if err == nil {
 			t.Errorf("Expected error in processing bid")
 		}
@@ -75,9 +81,165 @@ func TestProcessNewBid(t *testing.T) {
 			},
 		}
 
-		err := b.processNewBid("NewBid", 12, *bid)
+		err := b.processNewBid("NewBid", 12, testPubkey, *bid, proposer.Options{})
 		if err == nil {
 			t.Errorf("Expected error in processing bid with no header")
 		}
 	})
 }
+
+func TestSelectedHeaderPerParent(t *testing.T) {
+	b := NewBlockAggregatorService()
+
+	// Two beacon nodes following different heads request headers for the same slot
+	otherParentHash := "0x" + strings.Repeat("ab", 32)
+	newBid := func(blockHash byte, value uint64, parentHash string) spec.VersionedSignedBuilderBid {
+		bid := testAuctionBid(blockHash, value)
+		bid.Capella.Message.Header.ParentHash = _HexToHash(parentHash)
+		return bid
+	}
+	for _, bid := range []spec.VersionedSignedBuilderBid{newBid(1, 20, testParentHash), newBid(2, 10, otherParentHash)} {
+		if err := b.processNewBid("relay", 100, testPubkey, bid, proposer.Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for parentHash, want := range map[string]uint64{testParentHash: 20, otherParentHash: 10} {
+		// the request parent hash is case insensitive
+		slotHeader, err := b.Data.GetSelectedSlotHeader(100, "0x"+strings.ToUpper(parentHash[2:]), testPubkey)
+		if err != nil {
+			t.Fatalf("Expected a header for parent %s: %v", parentHash, err)
+		}
+		if slotHeader.Value.Uint64() != want {
+			t.Errorf("Expected the header built on parent %s worth %d, got %d", parentHash, want, slotHeader.Value.Uint64())
+		}
+	}
+
+	if _, err := b.Data.GetSelectedSlotHeader(100, testParentHash, "0x"+strings.Repeat("00", 48)); err == nil {
+		t.Error("Expected no header for another proposer")
+	}
+}
+
+func TestSelectBids(t *testing.T) {
+	b := NewBlockAggregatorService()
+	newBid := func(module string, value uint64) sourceBid {
+		return sourceBid{module: module, response: spec.VersionedSignedBuilderBid{
+			Version: consensusspec.DataVersionCapella,
+			Capella: &capella.SignedBuilderBid{
+				Message: &capella.BuilderBid{Value: uint256.NewInt(value)},
+			},
+		}}
+	}
+	bids := []sourceBid{newBid("relay", 30), newBid("builder", 20), newBid("external", 5), newBid("builder", 10)}
+
+	t.Run("HighestValue", func(t *testing.T) {
+		selected := b.selectBids(bids, proposer.Options{MinBid: "10"})
+		if len(selected) != 3 {
+			t.Errorf("Expected the bid below the min bid to be dropped, got %d bids", len(selected))
+		}
+	})
+
+	t.Run("SourcePriority", func(t *testing.T) {
+		options := proposer.Options{BlockSources: []string{"builder", "relay", "external"}, Selection: proposer.SelectionSourcePriority}
+		selected := b.selectBids(bids, options)
+		if len(selected) != 2 || selected[0].module != "builder" || selected[1].module != "builder" {
+			t.Errorf("Expected only the bids of the preferred block source, got %+v", selected)
+		}
+
+		options.MinBid = "25"
+		selected = b.selectBids(bids, options)
+		if len(selected) != 1 || selected[0].module != "relay" {
+			t.Errorf("Expected the next block source when the preferred one has no bid above the min bid, got %+v", selected)
+		}
+	})
+}
+
+func TestBuilderLists(t *testing.T) {
+	b := NewBlockAggregatorService()
+	newBid := func(builder byte, blockHash byte) spec.VersionedSignedBuilderBid {
+		bid := testAuctionBid(blockHash, 10)
+		bid.Capella.Message.Pubkey[0] = builder
+		return bid
+	}
+	builder := func(b byte) string {
+		bid := newBid(b, 0)
+		pubkey, _ := bid.Builder()
+		return pubkey.String()
+	}
+
+	b.cfg.BuilderDenylist = []string{builder(1)}
+	options := proposer.Options{BuilderAllowlist: []string{builder(2)}}
+
+	for i, bid := range []spec.VersionedSignedBuilderBid{newBid(1, 1), newBid(2, 2), newBid(3, 3)} {
+		if err := b.processNewBid("relay", 100, testPubkey, bid, options); err != nil {
+			t.Fatalf("Expected rejected bids not to fail processing, bid %d: %v", i, err)
+		}
+	}
+
+	for i, bid := range []spec.VersionedSignedBuilderBid{newBid(1, 1), newBid(2, 2), newBid(3, 3)} {
+		blockHash, _ := bid.BlockHash()
+		_, err := b.Data.GetSlotHeaderByHash(blockHash.String())
+		if accepted := err == nil; accepted != (i == 1) {
+			t.Errorf("Expected only the bid of the allowed builder to be kept, bid %d kept: %t", i, accepted)
+		}
+	}
+
+	if reason := b.builderRejection(builder(1), proposer.Options{}); reason != builderDenied {
+		t.Errorf("Expected the globally denied builder to be denied for every proposer, got %q", reason)
+	}
+	if reason := b.builderRejection(builder(3), proposer.Options{}); reason != "" {
+		t.Errorf("Expected builders to be accepted without an allow list, got %q", reason)
+	}
+	if reason := b.builderRejection(builder(3), options); reason != builderNotAllowed {
+		t.Errorf("Expected a builder missing from the proposer allow list to be refused, got %q", reason)
+	}
+	if rejected := rejectedBuilderBids.Get(builder(1)); rejected == nil || rejected.String() != "1" {
+		t.Errorf("Expected the rejected bid to be counted, got %v", rejected)
+	}
+}
+
+func TestForkVersion(t *testing.T) {
+	b := NewBlockAggregatorService()
+	if err := b.Configure(common.ModuleFlags{config.NetworkFlag.Name: network.Holesky}); err != nil {
+		t.Fatal(err)
+	}
+
+	// capella starts at epoch 256 and electra at epoch 115968 on holesky, electra has no builder types
+	// in this build so its slots get no bids
+	cases := []struct {
+		slot     uint64
+		accepted bool
+	}{
+		{255 * 32, false},
+		{256 * 32, true},
+		{115968 * 32, false},
+	}
+	for i, c := range cases {
+		bid := testAuctionBid(byte(i+1), 10)
+		if err := b.processNewBid("relay", c.slot, testPubkey, bid, proposer.Options{}); err != nil {
+			t.Fatalf("Expected rejected bids not to fail processing, slot %d: %v", c.slot, err)
+		}
+		blockHash, _ := bid.BlockHash()
+		if _, err := b.Data.GetSlotHeaderByHash(blockHash.String()); (err == nil) != c.accepted {
+			t.Errorf("Expected the capella bid for slot %d accepted %t", c.slot, c.accepted)
+		}
+	}
+
+	if rejected := rejectedForkBids.Get("relay"); rejected == nil || rejected.String() != "2" {
+		t.Errorf("Expected the rejected bids to be counted, got %v", rejected)
+	}
+
+	// a mainnet slot after the electra epoch is refused
+	mainnet := NewBlockAggregatorService()
+	if err := mainnet.Configure(common.ModuleFlags{config.NetworkFlag.Name: network.Mainnet}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mainnet.checkForkVersion(364032*32+1, consensusspec.DataVersionDeneb); !errors.Is(err, network.ErrUnsupportedFork) {
+		t.Errorf("Expected deneb bids for electra slots to be refused, got %v", err)
+	}
+
+	// without a network the version of the block source is trusted
+	if err := NewBlockAggregatorService().checkForkVersion(0, consensusspec.DataVersionDeneb); err != nil {
+		t.Errorf("Expected no fork check without a network, got %v", err)
+	}
+}
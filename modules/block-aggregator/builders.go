@@ -0,0 +1,35 @@
+package blockaggregator
+
+import (
+	"expvar"
+	"strings"
+
+	"github.com/pon-network/mev-plus/common/proposer"
+)
+
+const (
+	// builderDenied is a bid refused as its builder is on a deny list
+	builderDenied = "denied"
+	// builderNotAllowed is a bid refused as its builder is missing from an allow list
+	builderNotAllowed = "not_allowed"
+)
+
+// rejectedBuilderBids counts the bids refused for their builder pubkey, by builder pubkey
+var rejectedBuilderBids = expvar.NewMap("blockAggregator.rejectedBuilderBids")
+
+// builderRejection returns why bids from the builder pubkey are refused for the proposer, empty if they are accepted.
+// The global lists apply to every proposer on top of the proposer's own lists.
+func (b *BlockAggregatorService) builderRejection(builder string, options proposer.Options) string {
+	builder = strings.ToLower(builder)
+	global := proposer.Options{BuilderAllowlist: b.cfg.BuilderAllowlist, BuilderDenylist: b.cfg.BuilderDenylist}
+
+	for _, o := range []proposer.Options{global, options} {
+		if !(proposer.Options{BuilderDenylist: o.BuilderDenylist}).AllowsBuilder(builder) {
+			return builderDenied
+		}
+		if !o.AllowsBuilder(builder) {
+			return builderNotAllowed
+		}
+	}
+	return ""
+}
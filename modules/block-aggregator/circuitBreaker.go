@@ -0,0 +1,174 @@
+package blockaggregator
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+)
+
+var errSlowBlockSource = errors.New("block source exceeded the latency threshold")
+
+// circuitBreaker tracks the getHeader health of a block source
+type circuitBreaker struct {
+	state               aggregatorCommon.CircuitBreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	lastError           string
+	lastLatency         time.Duration
+}
+
+// circuitBreakers trip a block source open after consecutive failed or slow getHeader calls, so it is skipped
+// until a status call succeeds once the open duration has passed
+type circuitBreakers struct {
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+
+	failureThreshold int
+	latencyThreshold time.Duration
+	openDuration     time.Duration
+}
+
+func newCircuitBreakers(failureThreshold int, latencyThreshold, openDuration time.Duration) *circuitBreakers {
+	return &circuitBreakers{
+		breakers:         make(map[string]*circuitBreaker),
+		failureThreshold: failureThreshold,
+		latencyThreshold: latencyThreshold,
+		openDuration:     openDuration,
+	}
+}
+
+func (c *circuitBreakers) get(module string) *circuitBreaker {
+	breaker, ok := c.breakers[module]
+	if !ok {
+		breaker = &circuitBreaker{state: aggregatorCommon.CircuitBreakerClosed}
+		c.breakers[module] = breaker
+	}
+	return breaker
+}
+
+// allow returns whether getHeader can be called on the block source, and whether a half-open
+// status probe of the block source should be started
+func (c *circuitBreakers) allow(module string) (allowed bool, probe bool) {
+	if c.failureThreshold == 0 {
+		return true, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker := c.get(module)
+	switch breaker.state {
+	case aggregatorCommon.CircuitBreakerOpen:
+		if time.Since(breaker.openedAt) < c.openDuration {
+			return false, false
+		}
+		// Only one probe is started, the source stays skipped while it is half-open
+		breaker.state = aggregatorCommon.CircuitBreakerHalfOpen
+		return false, true
+	case aggregatorCommon.CircuitBreakerHalfOpen:
+		return false, false
+	default:
+		return true, false
+	}
+}
+
+// record counts the outcome of a getHeader call and returns whether it tripped the breaker open.
+// A call slower than the latency threshold is a failure, a no content response is not.
+func (c *circuitBreakers) record(module string, latency time.Duration, err error) (tripped bool) {
+	if c.failureThreshold == 0 {
+		return false
+	}
+
+	if isNoContentError(err) {
+		err = nil
+	}
+	if err == nil && c.latencyThreshold > 0 && latency > c.latencyThreshold {
+		err = errSlowBlockSource
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker := c.get(module)
+	breaker.lastLatency = latency
+	if err == nil {
+		breaker.consecutiveFailures = 0
+		return false
+	}
+
+	breaker.consecutiveFailures++
+	breaker.lastError = err.Error()
+	if breaker.state == aggregatorCommon.CircuitBreakerClosed && breaker.consecutiveFailures >= c.failureThreshold {
+		breaker.state = aggregatorCommon.CircuitBreakerOpen
+		breaker.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// probed closes the breaker if the half-open status probe succeeded, otherwise opens it again
+func (c *circuitBreakers) probed(module string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker := c.get(module)
+	if err != nil {
+		breaker.state = aggregatorCommon.CircuitBreakerOpen
+		breaker.openedAt = time.Now()
+		breaker.lastError = err.Error()
+		return
+	}
+
+	breaker.state = aggregatorCommon.CircuitBreakerClosed
+	breaker.consecutiveFailures = 0
+}
+
+func (c *circuitBreakers) status() []aggregatorCommon.CircuitBreakerStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	statuses := make([]aggregatorCommon.CircuitBreakerStatus, 0, len(c.breakers))
+	for module, breaker := range c.breakers {
+		status := aggregatorCommon.CircuitBreakerStatus{
+			Module:              module,
+			State:               breaker.state,
+			ConsecutiveFailures: breaker.consecutiveFailures,
+			LastError:           breaker.lastError,
+			LastLatencyMs:       breaker.lastLatency.Milliseconds(),
+		}
+		if breaker.state != aggregatorCommon.CircuitBreakerClosed {
+			status.OpenedAt = breaker.openedAt.Unix()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Module < statuses[j].Module })
+
+	return statuses
+}
+
+func (b *BlockAggregatorService) newCircuitBreakers() *circuitBreakers {
+	return newCircuitBreakers(
+		int(b.cfg.BreakerFailureThreshold),
+		time.Duration(b.cfg.BreakerLatencyThreshold)*time.Millisecond,
+		time.Duration(b.cfg.BreakerOpenDuration)*time.Millisecond,
+	)
+}
+
+// probeBlockSource checks a half-open block source with a status call
+func (b *BlockAggregatorService) probeBlockSource(module string) {
+	err := b.coreClient.Call(nil, module+"_status", false, nil)
+	b.circuitBreakers.probed(module, err)
+	if err != nil {
+		b.log.WithError(err).WithField("module", module).Warn("block source still failing, circuit breaker open")
+		return
+	}
+	b.log.WithField("module", module).Info("block source recovered, circuit breaker closed")
+}
+
+// CircuitBreakers returns the circuit breaker state of the block sources
+func (b *BlockAggregatorService) CircuitBreakers() ([]aggregatorCommon.CircuitBreakerStatus, error) {
+	return b.circuitBreakers.status(), nil
+}
@@ -0,0 +1,80 @@
+package blockaggregator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pon-network/mev-plus/common"
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	errFailed := errors.New("relay unavailable")
+
+	t.Run("TripsAfterConsecutiveFailures", func(t *testing.T) {
+		c := newCircuitBreakers(3, 0, time.Hour)
+
+		c.record("relay", time.Millisecond, errFailed)
+		c.record("relay", time.Millisecond, errFailed)
+		// A success resets the count, and no bid is not a failure
+		c.record("relay", time.Millisecond, &common.NoContentError{Message: "no bid"})
+		c.record("relay", time.Millisecond, errFailed)
+		c.record("relay", time.Millisecond, errFailed)
+		if allowed, _ := c.allow("relay"); !allowed {
+			t.Fatal("Expected the breaker to stay closed below the failure threshold")
+		}
+
+		if !c.record("relay", time.Millisecond, errFailed) {
+			t.Fatal("Expected the breaker to trip at the failure threshold")
+		}
+		if allowed, probe := c.allow("relay"); allowed || probe {
+			t.Fatal("Expected an open breaker to skip the block source without probing before the open duration")
+		}
+
+		status := c.status()
+		if len(status) != 1 || status[0].State != aggregatorCommon.CircuitBreakerOpen || status[0].LastError != errFailed.Error() {
+			t.Errorf("Unexpected circuit breaker status %+v", status)
+		}
+	})
+
+	t.Run("SlowCallsAreFailures", func(t *testing.T) {
+		c := newCircuitBreakers(1, 100*time.Millisecond, time.Hour)
+		if !c.record("builder", time.Second, nil) {
+			t.Error("Expected a call above the latency threshold to trip the breaker")
+		}
+	})
+
+	t.Run("HalfOpenProbe", func(t *testing.T) {
+		c := newCircuitBreakers(1, 0, 0)
+		c.record("relay", time.Millisecond, errFailed)
+
+		if allowed, probe := c.allow("relay"); allowed || !probe {
+			t.Fatal("Expected a probe once the open duration has passed")
+		}
+		if allowed, probe := c.allow("relay"); allowed || probe {
+			t.Fatal("Expected a single probe while half-open")
+		}
+
+		c.probed("relay", errFailed)
+		if status := c.status(); status[0].State != aggregatorCommon.CircuitBreakerOpen {
+			t.Fatalf("Expected a failed probe to open the breaker again, got %s", status[0].State)
+		}
+
+		c.allow("relay")
+		c.probed("relay", nil)
+		if allowed, _ := c.allow("relay"); !allowed {
+			t.Fatal("Expected a successful probe to close the breaker")
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c := newCircuitBreakers(0, 0, 0)
+		for i := 0; i < 10; i++ {
+			c.record("relay", time.Millisecond, errFailed)
+		}
+		if allowed, _ := c.allow("relay"); !allowed {
+			t.Error("Expected circuit breakers to be disabled with a failure threshold of 0")
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package blockaggregator
+
+import (
+	"time"
+)
+
+// chainClockSlotStartTime is the chain clock call returning the start time of a slot in unix milliseconds
+const chainClockSlotStartTime = "chainClock_slotStartTime"
+
+// chainClockSlotStart returns the start time of the slot from the chain clock module, it is false if the
+// chain clock is not connected
+func (b *BlockAggregatorService) chainClockSlotStart(slot uint64) (time.Time, bool) {
+	if b.coreClient == nil || !b.coreClient.HasCallback(chainClockSlotStartTime) {
+		return time.Time{}, false
+	}
+	var slotStart int64
+	if err := b.coreClient.Call(&slotStart, chainClockSlotStartTime, false, nil, slot); err != nil {
+		b.log.WithError(err).WithField("slot", slot).Warn("failed to get the slot start time from the chain clock")
+		return time.Time{}, false
+	}
+	return time.UnixMilli(slotStart), true
+}
@@ -0,0 +1,111 @@
+package blockaggregator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
+	coreCommon "github.com/pon-network/mev-plus/core/common"
+	chainclock "github.com/pon-network/mev-plus/modules/chain-clock"
+	clockConfig "github.com/pon-network/mev-plus/modules/chain-clock/config"
+)
+
+// connectChainClock connects the aggregator to a chain clock service, relaying messages between
+// the two clients the way the core does
+func connectChainClock(t *testing.T, b *BlockAggregatorService, clock *chainclock.ChainClockService) {
+	registry := coreCommon.ModuleRegistry{}
+	if err := registry.RegisterName(clock.Name(), clock); err != nil {
+		t.Fatal(err)
+	}
+
+	knownCallbacks := map[string]bool{"core_ping": true}
+	var clockCallbacks map[string]*coreCommon.Callback
+	for _, module := range registry.Modules() {
+		clockCallbacks = module.Callbacks
+		for method := range module.Callbacks {
+			knownCallbacks[module.Name+"_"+method] = true
+		}
+	}
+
+	_, clockClient, clockChans, err := coreCommon.NewClient(context.Background(), clock.Name(), clockCallbacks, knownCallbacks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, aggregatorClient, aggregatorChans, err := coreCommon.NewClient(context.Background(), b.Name(), nil, knownCallbacks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	incoming := map[string]chan coreCommon.JsonRPCMessage{
+		clock.Name(): clockChans.Incoming,
+		b.Name():     aggregatorChans.Incoming,
+	}
+	route := func(origin string, msg coreCommon.JsonRPCMessage) {
+		target := msg.Origin
+		if !msg.IsResponse() {
+			target = msg.Namespace()
+			if msg.Origin == "" {
+				msg.Origin = origin
+			}
+		}
+		if ch, ok := incoming[target]; ok {
+			ch <- msg
+		}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case msg := <-clockChans.Outgoing:
+				route(clock.Name(), msg)
+			case msg := <-aggregatorChans.Outgoing:
+				route(b.Name(), msg)
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		wg.Wait()
+		clockClient.Close()
+		aggregatorClient.Close()
+	})
+
+	b.coreClient = aggregatorClient
+}
+
+func TestChainClockSlotStart(t *testing.T) {
+	b := NewBlockAggregatorService()
+	if _, ok := b.slotStart(100); ok {
+		t.Error("Expected no slot start without the chain clock")
+	}
+
+	clock := chainclock.NewChainClockService()
+	err := clock.Configure(common.ModuleFlags{
+		clockConfig.NetworkFlag.Name:     network.Holesky,
+		clockConfig.GenesisTimeFlag.Name: "1000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	connectChainClock(t, b, clock)
+
+	slotStart, ok := b.slotStart(100)
+	if !ok || !slotStart.Equal(time.Unix(1000+100*12, 0)) {
+		t.Errorf("Expected slot 100 to start at %d, got %s (%v)", 1000+100*12, slotStart, ok)
+	}
+
+	// The header cache of a slot long passed expires at the start of the next slot
+	b.cfg.HeaderCacheDuration = 60000
+	if expiry := b.headerCacheExpiry(100); !expiry.Equal(time.Unix(1000+101*12, 0)) {
+		t.Errorf("Expected the header cache to expire at the start of slot 101, got %s", expiry)
+	}
+}
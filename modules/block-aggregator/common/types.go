@@ -1 +1,75 @@
-package common
\ No newline at end of file
+package common
+
+// BlockEquivocation is the payload of the core_blockEquivocation event, sent when a proposer
+// submits a blinded block that conflicts with one already unblinded for the same slot
+type BlockEquivocation struct {
+	Slot                 uint64 `json:"slot,string"`
+	ProposerIndex        uint64 `json:"proposer_index,string"`
+	BlockRoot            string `json:"block_root"`
+	BlockHash            string `json:"block_hash"`
+	ConflictingBlockRoot string `json:"conflicting_block_root"`
+	ConflictingBlockHash string `json:"conflicting_block_hash"`
+}
+
+// CircuitBreakerState is the state of the circuit breaker of a block source
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed block sources are called on getHeader
+	CircuitBreakerClosed CircuitBreakerState = "closed"
+	// CircuitBreakerOpen block sources are skipped on getHeader after failing too often
+	CircuitBreakerOpen CircuitBreakerState = "open"
+	// CircuitBreakerHalfOpen block sources are skipped while a status call probes if they recovered
+	CircuitBreakerHalfOpen CircuitBreakerState = "half-open"
+)
+
+// CircuitBreakerStatus is the circuit breaker state of a block source
+type CircuitBreakerStatus struct {
+	Module              string              `json:"module"`
+	State               CircuitBreakerState `json:"state"`
+	ConsecutiveFailures int                 `json:"consecutive_failures"`
+	LastError           string              `json:"last_error,omitempty"`
+	LastLatencyMs       int64               `json:"last_latency_ms"`
+	OpenedAt            int64               `json:"opened_at,omitempty"`
+}
+
+// SourceReputation is the payload delivery history and reputation score of a block source
+type SourceReputation struct {
+	Module            string  `json:"module"`
+	Score             float64 `json:"score"`
+	HeadersWon        uint64  `json:"headers_won"`
+	PayloadsDelivered uint64  `json:"payloads_delivered"`
+	PayloadFailures   uint64  `json:"payload_failures"`
+	PayloadLatencyMs  int64   `json:"payload_latency_ms"`
+	LastFailure       string  `json:"last_failure,omitempty"`
+}
+
+// BidRecord is a bid received from a block source for a slot
+type BidRecord struct {
+	Module     string `json:"module"`
+	BlockHash  string `json:"block_hash"`
+	Builder    string `json:"builder"`
+	Value      string `json:"value"`       // in wei
+	ReceivedAt int64  `json:"received_at"` // unix milliseconds
+}
+
+// PayloadDelivery is the outcome of requesting the payload of the selected header from its block source
+type PayloadDelivery struct {
+	Module      string `json:"module"`
+	BlockHash   string `json:"block_hash"`
+	Delivered   bool   `json:"delivered"`
+	Published   bool   `json:"published"` // the blinded block was submitted for the block source to publish, no payload was returned
+	Error       string `json:"error,omitempty"`
+	LatencyMs   int64  `json:"latency_ms"`
+	RequestedAt int64  `json:"requested_at"` // unix milliseconds
+}
+
+// SlotHistory is the auction and payload delivery history of a slot
+type SlotHistory struct {
+	Slot           uint64           `json:"slot,string"`
+	ParentHash     string           `json:"parent_hash"`
+	ProposerPubkey string           `json:"proposer_pubkey"`
+	Bids           []BidRecord      `json:"bids"`
+	Selected       *BidRecord       `json:"selected,omitempty"`
+	Delivery       *PayloadDelivery `json:"delivery,omitempty"`
+}
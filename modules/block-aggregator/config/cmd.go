@@ -19,8 +19,25 @@ func NewCommand() *cli.Command {
 
 func blockAggregatorFlags() []cli.Flag {
 	return []cli.Flag{
-		GenesisTimeFlag,
+		NetworkFlag,
 		AuctionDurationFlag,
-		SlotDurationFlag,
+		AuctionPollIntervalFlag,
+		AuctionValueThresholdFlag,
+		AuctionMaxLatencyFlag,
+		HeaderCacheDurationFlag,
+		SourceTimeoutFlag,
+		SourceTimeoutsFlag,
+		BreakerFailureThresholdFlag,
+		BreakerLatencyThresholdFlag,
+		BreakerOpenDurationFlag,
+		ReputationModeFlag,
+		ReputationMinScoreFlag,
+		ReputationRecoveryHalfLifeFlag,
+		BuilderAllowlistFlag,
+		BuilderDenylistFlag,
+		DataDirFlag,
+		HistoryRetentionFlag,
+		ProposerConfigFlag,
+		ExecutionNodeURLFlag,
 	}
 }
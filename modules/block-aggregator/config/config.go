@@ -1,13 +1,60 @@
 package config
 
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
 type BlockAggregatorConfig struct {
-	GenesisTime        uint64
 	AuctionDuration	uint64 // in seconds
-	SlotDuration		uint64 // in seconds
+	AuctionPollInterval	uint64 // in milliseconds
+	AuctionValueThreshold	*big.Int // in wei
+	AuctionMaxLatency	uint64 // in milliseconds
+	HeaderCacheDuration	uint64 // in milliseconds
+	SourceTimeout	uint64 // in milliseconds
+	SourceTimeouts	map[string]uint64 // in milliseconds, by block source module
+	BreakerFailureThreshold	uint64
+	BreakerLatencyThreshold	uint64 // in milliseconds
+	BreakerOpenDuration	uint64 // in milliseconds
+	ReputationMode	string
+	ReputationMinScore	float64
+	ReputationRecoveryHalfLife	uint64 // in milliseconds
+	BuilderAllowlist	[]string // builder pubkeys, nil for any builder
+	BuilderDenylist	[]string // builder pubkeys
+	DataDir		string
+	HistoryRetention	uint64 // in slots
+	ProposerConfig	string
+	ExecutionNodeURL	string
 }
 
 var BlockAggregatorConfigDefaults = BlockAggregatorConfig{
-	GenesisTime:        0,
 	AuctionDuration:	0,
-	SlotDuration:		12,
-}
\ No newline at end of file
+	AuctionPollInterval:	0,
+	AuctionValueThreshold:	nil,
+	AuctionMaxLatency:	0,
+	HeaderCacheDuration:	2000,
+	SourceTimeout:	950,
+	SourceTimeouts:	map[string]uint64{},
+	BreakerFailureThreshold:	5,
+	BreakerLatencyThreshold:	0,
+	BreakerOpenDuration:	60000,
+	ReputationMode:	"off",
+	ReputationMinScore:	0.5,
+	ReputationRecoveryHalfLife:	3600000,
+	BuilderAllowlist:	nil,
+	BuilderDenylist:	nil,
+	DataDir:		defaultDataDir(),
+	HistoryRetention:	50400, // one week
+	ProposerConfig:	"",
+	ExecutionNodeURL:	"",
+}
+
+// defaultDataDir is where block aggregator records are kept if no data directory is set
+func defaultDataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".mevPlus", ModuleName)
+}
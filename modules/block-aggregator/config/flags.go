@@ -6,24 +6,139 @@ import (
 )
 
 var (
-	GenesisTimeFlag = &cli.IntFlag{
-		Name:     ModuleName + "." + "genesis-time",
-		Usage:    "Set the genesis time (in seconds)",
+	NetworkFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "network",
+		Usage:    "Set the fork schedule bids are checked against from a network preset (mainnet, sepolia, goerli, holesky) or a consensus config.yaml path or directory for devnets, slot times are taken from the chain clock module",
 		Category: utils.BlockAggregatorCategory,
-		Value:    int(BlockAggregatorConfigDefaults.GenesisTime),
 	}
 
 	AuctionDurationFlag = &cli.IntFlag{
 		Name:     ModuleName + "." + "auction-duration",
-		Usage:    "Set the auction duration (in seconds)",
+		Usage:    "Set how long after the slot start, as kept by the chain clock module, bids are collected (in seconds)",
 		Category: utils.BlockAggregatorCategory,
 		Value:    int(BlockAggregatorConfigDefaults.AuctionDuration),
 	}
 
-	SlotDurationFlag = &cli.IntFlag{
-		Name:     ModuleName + "." + "slot-duration",
-		Usage:    "Set the slot duration (in seconds)",
+	AuctionPollIntervalFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "auction-poll-interval",
+		Usage:    "Set how often block sources are polled for better bids until the auction deadline (in milliseconds, 0 to poll once)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.AuctionPollInterval),
+	}
+
+	AuctionValueThresholdFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "auction-value-threshold",
+		Usage:    "End the auction early once a bid reaches this value (in wei, 0 to disable)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    "0",
+	}
+
+	AuctionMaxLatencyFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "auction-max-latency",
+		Usage:    "End the auction no later than this long after the slot start, whatever the auction duration (in milliseconds, 0 to disable)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.AuctionMaxLatency),
+	}
+
+	HeaderCacheDurationFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "header-cache-duration",
+		Usage:    "Set how long a selected header is returned to repeat getHeader requests within the same slot (in milliseconds, 0 to disable)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.HeaderCacheDuration),
+	}
+
+	DataDirFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "data-dir",
+		Usage:    "Set the directory where records of unblinded blocks and the auction and payload delivery history are persisted, the module does not start without one",
+		Category: utils.BlockAggregatorCategory,
+		Value:    BlockAggregatorConfigDefaults.DataDir,
+	}
+
+	HistoryRetentionFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "history-retention",
+		Usage:    "Set the number of slots of auction and payload delivery history kept in the data directory, 0 to keep all history",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.HistoryRetention),
+	}
+
+	ProposerConfigFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "proposer-config",
+		Usage:    "Set the path of a JSON proposer config selecting the block sources, relays, min-bid (in wei) and bid selection strategy per validator pubkey, with a default section",
+		Category: utils.BlockAggregatorCategory,
+		Value:    BlockAggregatorConfigDefaults.ProposerConfig,
+	}
+
+	ExecutionNodeURLFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "execution-node-url",
+		Usage:    "Set the JSON-RPC URL of an execution node, used to read the gas limit of the parent of bids so the gas limit of bids is checked against the proposer registration",
+		Category: utils.BlockAggregatorCategory,
+		Value:    BlockAggregatorConfigDefaults.ExecutionNodeURL,
+	}
+
+	SourceTimeoutFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "source-timeout",
+		Usage:    "Set the timeout of getHeader calls to block sources, responses after it are dropped (in milliseconds, 0 for no timeout)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.SourceTimeout),
+	}
+
+	SourceTimeoutsFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "source-timeouts",
+		Usage:    "Set the getHeader timeout of specific block sources as a comma separated list of module=milliseconds, overriding source-timeout",
+		Category: utils.BlockAggregatorCategory,
+	}
+
+	BreakerFailureThresholdFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "breaker-failure-threshold",
+		Usage:    "Set the number of consecutive failed or slow getHeader calls after which a block source is skipped (0 to disable circuit breakers)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.BreakerFailureThreshold),
+	}
+
+	BreakerLatencyThresholdFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "breaker-latency-threshold",
+		Usage:    "Set the getHeader latency above which a block source call is counted as failed (in milliseconds, 0 to disable)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.BreakerLatencyThreshold),
+	}
+
+	BreakerOpenDurationFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "breaker-open-duration",
+		Usage:    "Set how long a tripped block source is skipped before a status call probes if it recovered (in milliseconds)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.BreakerOpenDuration),
+	}
+
+	ReputationModeFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "reputation-mode",
+		Usage:    "Set how the payload delivery reputation of block sources is used in bid selection: off, discount (rank bids by value times reputation score) or exclude (drop bids of block sources below the minimum score)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    BlockAggregatorConfigDefaults.ReputationMode,
+	}
+
+	ReputationMinScoreFlag = &cli.Float64Flag{
+		Name:     ModuleName + "." + "reputation-min-score",
+		Usage:    "Set the reputation score, from 0 to 1, below which bids of a block source are dropped in the exclude reputation mode",
+		Category: utils.BlockAggregatorCategory,
+		Value:    BlockAggregatorConfigDefaults.ReputationMinScore,
+	}
+
+	ReputationRecoveryHalfLifeFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "reputation-recovery-half-life",
+		Usage:    "Set the time without payload outcomes after which the reputation score of a block source has recovered half of its gap to 1, so excluded block sources are selected again (in milliseconds, 0 to disable)",
+		Category: utils.BlockAggregatorCategory,
+		Value:    int(BlockAggregatorConfigDefaults.ReputationRecoveryHalfLife),
+	}
+
+	BuilderAllowlistFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "builder-allowlist",
+		Usage:    "Set a comma separated list of builder pubkeys whose bids are the only ones accepted from any block source",
+		Category: utils.BlockAggregatorCategory,
+	}
+
+	BuilderDenylistFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "builder-denylist",
+		Usage:    "Set a comma separated list of builder pubkeys whose bids are refused from any block source",
 		Category: utils.BlockAggregatorCategory,
-		Value:    int(BlockAggregatorConfigDefaults.SlotDuration),
 	}
 )
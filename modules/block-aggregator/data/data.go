@@ -65,11 +65,21 @@ func (d *AggregatorData) AddSlotHeader(slotHeader SlotHeader) error {
 	// any module, there is a chance that the payload is not available for the
 	// first 2 headers
 	sort.Slice(d.selectedSlotHeaders[slotHeader.Slot], func(i, j int) bool {
-		return d.selectedSlotHeaders[slotHeader.Slot][i].Value.Cmp(d.selectedSlotHeaders[slotHeader.Slot][j].Value) > 0
+		return d.selectedSlotHeaders[slotHeader.Slot][i].selectionValue().Cmp(d.selectedSlotHeaders[slotHeader.Slot][j].selectionValue()) > 0
 	})
-	if len(d.selectedSlotHeaders[slotHeader.Slot]) > 3 {
-		d.selectedSlotHeaders[slotHeader.Slot] = d.selectedSlotHeaders[slotHeader.Slot][:3]
+	// the headers are kept per parent hash and proposer, several may be requested in the same slot
+	var kept int
+	slotHeaders := d.selectedSlotHeaders[slotHeader.Slot][:0]
+	for _, header := range d.selectedSlotHeaders[slotHeader.Slot] {
+		if header.forRequest(slotHeader.ParentHash, slotHeader.ProposerPubkey) {
+			if kept == 3 {
+				continue
+			}
+			kept++
+		}
+		slotHeaders = append(slotHeaders, header)
 	}
+	d.selectedSlotHeaders[slotHeader.Slot] = slotHeaders
 
 	d.lastSlot = slotHeader.Slot
 
@@ -110,3 +120,17 @@ func (d *AggregatorData) GetSelectedSlotHeaders(slot uint64) (SlotHeader, error)
 
 	return d.selectedSlotHeaders[slot][0], nil
 }
+
+// GetSelectedSlotHeader returns the most valuable header for the slot built on the parent hash for the proposer
+func (d *AggregatorData) GetSelectedSlotHeader(slot uint64, parentHash, proposerPubkey string) (SlotHeader, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, slotHeader := range d.selectedSlotHeaders[slot] {
+		if slotHeader.forRequest(parentHash, proposerPubkey) {
+			return slotHeader, nil
+		}
+	}
+
+	return SlotHeader{}, fmt.Errorf("slot %v not found for parent %s and proposer %s", slot, parentHash, proposerPubkey)
+}
@@ -75,6 +75,26 @@ func TestAggregatorData(t *testing.T) {
 			t.Errorf("Retrieved selected slot header does not match the added one.")
 		}
 	})
+
+	t.Run("SelectBySelectionValue", func(t *testing.T) {
+		aggregator := NewAggregatorData()
+		discounted := SlotHeader{Slot: 142, BlockHash: "discounted", Value: big.NewInt(200), SelectionValue: big.NewInt(100)}
+		trusted := SlotHeader{Slot: 142, BlockHash: "trusted", Value: big.NewInt(150)}
+
+		for _, slotHeader := range []SlotHeader{discounted, trusted} {
+			if err := aggregator.AddSlotHeader(slotHeader); err != nil {
+				t.Fatalf("Error adding slot header: %v", err)
+			}
+		}
+
+		selectedSlotHeader, err := aggregator.GetSelectedSlotHeaders(142)
+		if err != nil {
+			t.Fatalf("Error retrieving selected slot header: %v", err)
+		}
+		if selectedSlotHeader.BlockHash != "trusted" {
+			t.Errorf("Expected the header with the highest selection value, got %s", selectedSlotHeader.BlockHash)
+		}
+	})
 }
 
 func TestAggregatorDataNegative(t *testing.T) {
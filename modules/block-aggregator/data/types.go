@@ -2,6 +2,7 @@ package data
 
 import (
 	"math/big"
+	"strings"
 
 	"github.com/attestantio/go-builder-client/spec"
 )
@@ -12,4 +13,23 @@ type SlotHeader struct {
 	Value      *big.Int
 	BlockHash  string
 	Bid        *spec.VersionedSignedBuilderBid
+
+	// ParentHash and ProposerPubkey are those of the getHeader request the header answers, in lower case
+	ParentHash     string
+	ProposerPubkey string
+
+	// SelectionValue ranks the header against other headers for the slot instead of Value if set,
+	// such as the value discounted by the reputation of the block source
+	SelectionValue *big.Int
+}
+
+func (h SlotHeader) forRequest(parentHash, proposerPubkey string) bool {
+	return h.ParentHash == strings.ToLower(parentHash) && h.ProposerPubkey == strings.ToLower(proposerPubkey)
+}
+
+func (h SlotHeader) selectionValue() *big.Int {
+	if h.SelectionValue != nil {
+		return h.SelectionValue
+	}
+	return h.Value
 }
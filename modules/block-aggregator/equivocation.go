@@ -0,0 +1,184 @@
+package blockaggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	"github.com/pon-network/mev-plus/common"
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	equivocationRecordsFile = "unblinded_blocks.json"
+
+	// records are kept for 2 epochs, the same as selected slot headers
+	equivocationRecordSlots = 64
+)
+
+var (
+	errBlockEquivocation = errors.New("conflicting blinded block already unblinded for this slot and proposer")
+	errNoDataDir         = errors.New("no data directory set to persist the records of unblinded blocks, set -" + config.DataDirFlag.Name)
+)
+
+type slotProposer struct {
+	Slot          uint64 `json:"slot,string"`
+	ProposerIndex uint64 `json:"proposer_index,string"`
+}
+
+// unblindedBlock is the record of a blinded block that was submitted to be unblinded
+type unblindedBlock struct {
+	slotProposer
+	BlockRoot string `json:"block_root"`
+	BlockHash string `json:"block_hash"`
+}
+
+// equivocationGuard records the block unblinded for every slot and proposer, and refuses to unblind
+// a different block for the same slot and proposer. Records are written to disk before a block is
+// accepted so the guard survives restarts.
+type equivocationGuard struct {
+	mu      sync.Mutex
+	path    string // empty to only keep records in memory
+	records map[slotProposer]unblindedBlock
+}
+
+func newEquivocationGuard(dataDir string) (*equivocationGuard, error) {
+	g := &equivocationGuard{records: make(map[slotProposer]unblindedBlock)}
+	if dataDir == "" {
+		return g, nil
+	}
+
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, err
+	}
+	g.path = filepath.Join(dataDir, equivocationRecordsFile)
+
+	raw, err := os.ReadFile(g.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return g, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []unblindedBlock
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("invalid unblinded block records %s: %w", g.path, err)
+	}
+	for _, record := range records {
+		g.records[record.slotProposer] = record
+	}
+
+	return g, nil
+}
+
+// check records the block if it is the first for its slot and proposer. If a different block was already
+// recorded it returns that block and errBlockEquivocation. Submitting the same block again is allowed.
+func (g *equivocationGuard) check(block unblindedBlock) (unblindedBlock, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if previous, ok := g.records[block.slotProposer]; ok {
+		if previous.BlockRoot != block.BlockRoot {
+			return previous, errBlockEquivocation
+		}
+		return previous, nil
+	}
+
+	g.records[block.slotProposer] = block
+	for key := range g.records {
+		if key.Slot+equivocationRecordSlots < block.Slot {
+			delete(g.records, key)
+		}
+	}
+
+	if err := g.persist(); err != nil {
+		// Without a persisted record the guard could be bypassed after a restart, so refuse the block
+		delete(g.records, block.slotProposer)
+		return unblindedBlock{}, fmt.Errorf("failed to persist unblinded block record: %w", err)
+	}
+
+	return block, nil
+}
+
+// persist atomically writes the records to disk
+func (g *equivocationGuard) persist() error {
+	if g.path == "" {
+		return nil
+	}
+
+	records := make([]unblindedBlock, 0, len(g.records))
+	for _, record := range g.records {
+		records = append(records, record)
+	}
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	tmp := g.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, g.path)
+}
+
+// checkEquivocation records the blinded block as unblinded for its slot and proposer. If a different block was
+// already unblinded for them it raises a block equivocation alert and refuses the block.
+func (b *BlockAggregatorService) checkEquivocation(block commonTypes.VersionedSignedBlindedBeaconBlock, slot phase0.Slot, proposerIndex phase0.ValidatorIndex, blockHash string) error {
+	blockRoot, err := blindedBlockRoot(block)
+	if err != nil {
+		return &common.InvalidParamsError{Message: err.Error()}
+	}
+
+	previous, err := b.equivocationGuard.check(unblindedBlock{
+		slotProposer: slotProposer{Slot: uint64(slot), ProposerIndex: uint64(proposerIndex)},
+		BlockRoot:    blockRoot.String(),
+		BlockHash:    blockHash,
+	})
+	if !errors.Is(err, errBlockEquivocation) {
+		return err
+	}
+
+	event := aggregatorCommon.BlockEquivocation{
+		Slot:                 uint64(slot),
+		ProposerIndex:        uint64(proposerIndex),
+		BlockRoot:            previous.BlockRoot,
+		BlockHash:            previous.BlockHash,
+		ConflictingBlockRoot: blockRoot.String(),
+		ConflictingBlockHash: blockHash,
+	}
+	b.log.WithFields(logrus.Fields{
+		"slot":                 event.Slot,
+		"proposerIndex":        event.ProposerIndex,
+		"blockRoot":            event.BlockRoot,
+		"blockHash":            event.BlockHash,
+		"conflictingBlockRoot": event.ConflictingBlockRoot,
+		"conflictingBlockHash": event.ConflictingBlockHash,
+	}).Error("BLOCK EQUIVOCATION: refused to unblind a second block for the same slot and proposer, check for duplicate validator keys")
+
+	// Alert all modules of the equivocation
+	_ = b.coreClient.Notify(context.Background(), "core_blockEquivocation", true, b.ModuleNotificationExclusions, event)
+
+	return &common.InvalidParamsError{Message: err.Error()}
+}
@@ -0,0 +1,92 @@
+package blockaggregator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/config"
+)
+
+func TestEquivocationGuard(t *testing.T) {
+	dataDir := t.TempDir()
+	g, err := newEquivocationGuard(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := unblindedBlock{slotProposer: slotProposer{Slot: 100, ProposerIndex: 7}, BlockRoot: "0x01", BlockHash: "0xaa"}
+	conflicting := unblindedBlock{slotProposer: first.slotProposer, BlockRoot: "0x02", BlockHash: "0xbb"}
+
+	if _, err := g.check(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.check(first); err != nil {
+		t.Fatalf("Expected the same block to be accepted again, got %v", err)
+	}
+	if _, err := g.check(unblindedBlock{slotProposer: slotProposer{Slot: 100, ProposerIndex: 8}, BlockRoot: "0x02"}); err != nil {
+		t.Fatalf("Expected a block from another proposer to be accepted, got %v", err)
+	}
+
+	// The records survive a restart
+	g, err = newEquivocationGuard(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := g.check(conflicting)
+	if !errors.Is(err, errBlockEquivocation) {
+		t.Fatalf("Expected a conflicting block to be refused, got %v", err)
+	}
+	if previous != first {
+		t.Errorf("Expected the conflict to return the first block, got %+v", previous)
+	}
+
+	// Old records are pruned
+	if _, err := g.check(unblindedBlock{slotProposer: slotProposer{Slot: 100 + equivocationRecordSlots + 1, ProposerIndex: 7}, BlockRoot: "0x03"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.check(conflicting); err != nil {
+		t.Errorf("Expected records older than %d slots to be pruned, got %v", equivocationRecordSlots, err)
+	}
+}
+
+func TestEquivocationGuardRestart(t *testing.T) {
+	b := NewBlockAggregatorService()
+	if err := b.Configure(common.ModuleFlags{config.DataDirFlag.Name: ""}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(); !errors.Is(err, errNoDataDir) {
+		t.Fatalf("Expected the module not to start without a data directory, got %v", err)
+	}
+
+	dataDir := t.TempDir()
+	first := unblindedBlock{slotProposer: slotProposer{Slot: 100, ProposerIndex: 7}, BlockRoot: "0x01", BlockHash: "0xaa"}
+	conflicting := unblindedBlock{slotProposer: first.slotProposer, BlockRoot: "0x02", BlockHash: "0xbb"}
+
+	b = NewBlockAggregatorService()
+	if err := b.Configure(common.ModuleFlags{config.DataDirFlag.Name: dataDir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.equivocationGuard.check(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The restarted module re-opens the guard from the data directory
+	b = NewBlockAggregatorService()
+	if err := b.Configure(common.ModuleFlags{config.DataDirFlag.Name: dataDir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+	if _, err := b.equivocationGuard.check(conflicting); !errors.Is(err, errBlockEquivocation) {
+		t.Errorf("Expected a conflicting block to be refused after a restart, got %v", err)
+	}
+}
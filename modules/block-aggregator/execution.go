@@ -0,0 +1,110 @@
+package blockaggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedGasLimits bounds the block gas limits kept by the execution client, bids of a slot share one parent
+const maxCachedGasLimits = 64
+
+// executionClient reads the gas limit of blocks from an execution node, over its JSON-RPC API
+type executionClient struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+
+	mu        sync.Mutex
+	gasLimits map[string]uint64 // by block hash
+}
+
+func newExecutionClient(url string, timeout time.Duration) *executionClient {
+	return &executionClient{
+		url:       url,
+		timeout:   timeout,
+		client:    &http.Client{},
+		gasLimits: make(map[string]uint64),
+	}
+}
+
+// gasLimit returns the gas limit of the block with the hash
+func (c *executionClient) gasLimit(blockHash string) (uint64, error) {
+	blockHash = strings.ToLower(blockHash)
+
+	c.mu.Lock()
+	gasLimit, ok := c.gasLimits[blockHash]
+	c.mu.Unlock()
+	if ok {
+		return gasLimit, nil
+	}
+
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_getBlockByHash",
+		"params":  []interface{}{blockHash, false},
+	})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("execution node returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result *struct {
+			GasLimit string `json:"gasLimit"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, err
+	}
+	if response.Error != nil {
+		return 0, errors.New(response.Error.Message)
+	}
+	if response.Result == nil {
+		return 0, fmt.Errorf("block %s not found", blockHash)
+	}
+	gasLimit, err = strconv.ParseUint(strings.TrimPrefix(response.Result.GasLimit, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gas limit %s: %w", response.Result.GasLimit, err)
+	}
+
+	c.mu.Lock()
+	if len(c.gasLimits) >= maxCachedGasLimits {
+		c.gasLimits = make(map[string]uint64)
+	}
+	c.gasLimits[blockHash] = gasLimit
+	c.mu.Unlock()
+
+	return gasLimit, nil
+}
@@ -0,0 +1,32 @@
+package blockaggregator
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+
+	consensusspec "github.com/attestantio/go-eth2-client/spec"
+)
+
+var errForkVersionMismatch = errors.New("bid version does not match the fork of the slot")
+
+// rejectedForkBids counts the bids refused for not being of the fork scheduled for their slot, by module
+var rejectedForkBids = expvar.NewMap("blockAggregator.rejectedForkBids")
+
+// checkForkVersion checks the version of a bid is the fork scheduled for the slot. Bids for slots of forks
+// without builder types in this build are refused. Without a configured network the fork schedule is
+// unknown and the version the block source returned is trusted.
+func (b *BlockAggregatorService) checkForkVersion(slot uint64, version consensusspec.DataVersion) error {
+	if b.network == nil {
+		return nil
+	}
+
+	expected, err := b.network.DataVersionAtSlot(slot)
+	if err != nil {
+		return err
+	}
+	if version != expected {
+		return fmt.Errorf("%w: %s bid for a %s slot", errForkVersionMismatch, version.String(), expected.String())
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package blockaggregator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
+	"golang.org/x/sync/singleflight"
+)
+
+// headerRequestKey identifies a getHeader request, identical requests from several beacon nodes share a key
+type headerRequestKey struct {
+	slot           uint64
+	parentHash     string
+	proposerPubkey string
+}
+
+func newHeaderRequestKey(slot uint64, parentHash, proposerPubkey string) headerRequestKey {
+	return headerRequestKey{
+		slot:           slot,
+		parentHash:     strings.ToLower(parentHash),
+		proposerPubkey: strings.ToLower(proposerPubkey),
+	}
+}
+
+func (k headerRequestKey) String() string {
+	return fmt.Sprintf("%d/%s/%s", k.slot, k.parentHash, k.proposerPubkey)
+}
+
+type cachedSlotHeader struct {
+	slotHeader data.SlotHeader
+	expiry     time.Time
+}
+
+// headerRequests runs one auction for concurrent identical getHeader requests and
+// answers repeat requests from a short lived cache
+type headerRequests struct {
+	group singleflight.Group
+
+	// expiry returns until when a header selected for the slot can be served from the cache
+	expiry func(slot uint64) time.Time
+
+	mu    sync.Mutex
+	cache map[headerRequestKey]cachedSlotHeader
+}
+
+func newHeaderRequests(expiry func(slot uint64) time.Time) *headerRequests {
+	return &headerRequests{
+		expiry: expiry,
+		cache:  make(map[headerRequestKey]cachedSlotHeader),
+	}
+}
+
+// do returns the cached header for the request if there is one, otherwise runs process,
+// sharing its result with any identical requests that arrive while it runs
+func (h *headerRequests) do(slot uint64, parentHash, proposerPubkey string, process func(slot uint64, parentHash, proposerPubkey string) (data.SlotHeader, error)) (data.SlotHeader, error) {
+	key := newHeaderRequestKey(slot, parentHash, proposerPubkey)
+
+	if slotHeader, ok := h.cached(key); ok {
+		return slotHeader, nil
+	}
+
+	result, err, _ := h.group.Do(key.String(), func() (interface{}, error) {
+		slotHeader, err := process(slot, parentHash, proposerPubkey)
+		if err != nil {
+			return slotHeader, err
+		}
+		h.store(key, slotHeader)
+		return slotHeader, nil
+	})
+
+	return result.(data.SlotHeader), err
+}
+
+func (h *headerRequests) cached(key headerRequestKey) (data.SlotHeader, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cached, ok := h.cache[key]
+	if !ok || time.Now().After(cached.expiry) {
+		return data.SlotHeader{}, false
+	}
+	return cached.slotHeader, true
+}
+
+func (h *headerRequests) store(key headerRequestKey, slotHeader data.SlotHeader) {
+	expiry := h.expiry(key.slot)
+	now := time.Now()
+	if !expiry.After(now) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Drop answers that have expired, they are only ever served within their slot
+	for cachedKey, cached := range h.cache {
+		if now.After(cached.expiry) {
+			delete(h.cache, cachedKey)
+		}
+	}
+	h.cache[key] = cachedSlotHeader{slotHeader: slotHeader, expiry: expiry}
+}
+
+// headerCacheExpiry returns until when a header selected for a slot is served to repeat requests,
+// which is the configured header cache duration but never beyond the end of the slot
+func (b *BlockAggregatorService) headerCacheExpiry(slot uint64) time.Time {
+	if b.cfg.HeaderCacheDuration == 0 {
+		return time.Time{}
+	}
+
+	expiry := time.Now().Add(time.Duration(b.cfg.HeaderCacheDuration) * time.Millisecond)
+	if slotEnd, ok := b.slotStart(slot + 1); ok && slotEnd.Before(expiry) {
+		expiry = slotEnd
+	}
+	return expiry
+}
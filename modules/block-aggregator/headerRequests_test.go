@@ -0,0 +1,143 @@
+package blockaggregator
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
+)
+
+const (
+	testParentHash = "0x534809bd2b6832edff8d8ce4cb0e50068804fd1ef432c8362ad708a74fdc0e46"
+	testPubkey     = "0x8a1d7b8dd64e0aafe7ea7b6c95065c9364cf99d38470c12ee807d55f7de1529ad29ce2c422e0b65e3d5a05c02caca249"
+)
+
+func TestHeaderRequests(t *testing.T) {
+	cacheFor := func(d time.Duration) func(uint64) time.Time {
+		return func(uint64) time.Time { return time.Now().Add(d) }
+	}
+
+	t.Run("ConcurrentRequestsShareOneAuction", func(t *testing.T) {
+		h := newHeaderRequests(cacheFor(0))
+		var auctions atomic.Int32
+		release := make(chan struct{})
+		process := func(slot uint64, parentHash, proposerPubkey string) (data.SlotHeader, error) {
+			auctions.Add(1)
+			<-release
+			return data.SlotHeader{ModuleName: "relay", Value: big.NewInt(1)}, nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]data.SlotHeader, 5)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var err error
+				results[i], err = h.do(10, testParentHash, testPubkey, process)
+				if err != nil {
+					t.Error(err)
+				}
+			}(i)
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		if n := auctions.Load(); n != 1 {
+			t.Fatalf("Expected one auction for identical requests, ran %d", n)
+		}
+		for _, result := range results {
+			if result.ModuleName != "relay" {
+				t.Errorf("Expected every request to get the shared result, got %+v", result)
+			}
+		}
+	})
+
+	t.Run("RepeatRequestsServedFromCache", func(t *testing.T) {
+		h := newHeaderRequests(cacheFor(time.Minute))
+		var auctions int
+		process := func(slot uint64, parentHash, proposerPubkey string) (data.SlotHeader, error) {
+			auctions++
+			return data.SlotHeader{ModuleName: "relay"}, nil
+		}
+
+		for i := 0; i < 3; i++ {
+			if _, err := h.do(10, testParentHash, testPubkey, process); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// The key is case insensitive
+		if _, err := h.do(10, testParentHash, "0x8A1D7B8DD64E0AAFE7EA7B6C95065C9364CF99D38470C12EE807D55F7DE1529AD29CE2C422E0B65E3D5A05C02CACA249", process); err != nil {
+			t.Fatal(err)
+		}
+		if auctions != 1 {
+			t.Fatalf("Expected repeat requests to be served from the cache, ran %d auctions", auctions)
+		}
+
+		if _, err := h.do(11, testParentHash, testPubkey, process); err != nil {
+			t.Fatal(err)
+		}
+		if auctions != 2 {
+			t.Fatalf("Expected a request for another slot to run its own auction, ran %d auctions", auctions)
+		}
+	})
+
+	t.Run("AnotherParentRunsItsOwnAuction", func(t *testing.T) {
+		h := newHeaderRequests(cacheFor(time.Minute))
+		process := func(slot uint64, parentHash, proposerPubkey string) (data.SlotHeader, error) {
+			return data.SlotHeader{ParentHash: parentHash}, nil
+		}
+
+		otherParentHash := "0x" + strings.Repeat("ab", 32)
+		for _, parentHash := range []string{testParentHash, otherParentHash, testParentHash} {
+			slotHeader, err := h.do(10, parentHash, testPubkey, process)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if slotHeader.ParentHash != parentHash {
+				t.Errorf("Expected the header for parent %s, got the one for %s", parentHash, slotHeader.ParentHash)
+			}
+		}
+	})
+
+	t.Run("ErrorsAreNotCached", func(t *testing.T) {
+		h := newHeaderRequests(cacheFor(time.Minute))
+		var auctions int
+		process := func(slot uint64, parentHash, proposerPubkey string) (data.SlotHeader, error) {
+			auctions++
+			return data.SlotHeader{}, errors.New("no bids")
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, err := h.do(10, testParentHash, testPubkey, process); err == nil {
+				t.Fatal("Expected the auction error to be returned")
+			}
+		}
+		if auctions != 2 {
+			t.Fatalf("Expected failed auctions to be retried, ran %d auctions", auctions)
+		}
+	})
+}
+
+func TestHeaderCacheExpiry(t *testing.T) {
+	b := NewBlockAggregatorService()
+
+	b.cfg.HeaderCacheDuration = 0
+	if expiry := b.headerCacheExpiry(1); !expiry.IsZero() {
+		t.Errorf("Expected no caching when the header cache duration is 0, got %s", expiry)
+	}
+
+	// The cache never outlives the slot
+	b.cfg.HeaderCacheDuration = 60000
+	genesis := time.Now()
+	b.slotStart = func(slot uint64) (time.Time, bool) { return genesis.Add(time.Duration(slot) * 12 * time.Second), true }
+	if expiry := b.headerCacheExpiry(0); expiry.After(genesis.Add(12 * time.Second)) {
+		t.Errorf("Expected the cache to expire by the end of the slot, got %s", expiry)
+	}
+}
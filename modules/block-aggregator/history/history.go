@@ -0,0 +1,172 @@
+// Package history keeps the auction and payload delivery history of each slot in an embedded bbolt database,
+// so it survives restarts for reporting and post-mortems of missed slots.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	slotsBucket = []byte("slots")
+
+	// ErrSlotNotFound is returned when there is no history for a slot
+	ErrSlotNotFound = errors.New("no history for the slot")
+)
+
+// Store is the slot history database
+type Store struct {
+	db *bolt.DB
+
+	// retention is the number of slots kept before the latest recorded auction
+	retention uint64
+}
+
+// Open opens or creates the history database at path, keeping retention slots of history
+func Open(path string, retention uint64) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(slotsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db, retention: retention}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// RecordAuction records the bids received for the slot and the header selected from them, and prunes slots past retention
+func (s *Store) RecordAuction(slot uint64, parentHash, proposerPubkey string, bids []aggregatorCommon.BidRecord, selected *aggregatorCommon.BidRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(slotsBucket)
+
+		history, err := get(bucket, slot)
+		if err != nil && !errors.Is(err, ErrSlotNotFound) {
+			return err
+		}
+		history.Slot = slot
+		history.ParentHash = parentHash
+		history.ProposerPubkey = proposerPubkey
+		history.Bids = append(history.Bids, bids...)
+		if selected != nil {
+			history.Selected = selected
+		}
+		if err := put(bucket, history); err != nil {
+			return err
+		}
+
+		return prune(bucket, slot, s.retention)
+	})
+}
+
+// RecordDelivery records the outcome of requesting the payload of the selected header for the slot
+func (s *Store) RecordDelivery(slot uint64, delivery aggregatorCommon.PayloadDelivery) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(slotsBucket)
+
+		history, err := get(bucket, slot)
+		if err != nil && !errors.Is(err, ErrSlotNotFound) {
+			return err
+		}
+		history.Slot = slot
+		history.Delivery = &delivery
+
+		return put(bucket, history)
+	})
+}
+
+// Slot returns the history of the slot
+func (s *Store) Slot(slot uint64) (aggregatorCommon.SlotHistory, error) {
+	var history aggregatorCommon.SlotHistory
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		history, err = get(tx.Bucket(slotsBucket), slot)
+		return err
+	})
+	return history, err
+}
+
+// Range returns the history of the slots from fromSlot to toSlot inclusive that have any
+func (s *Store) Range(fromSlot, toSlot uint64) ([]aggregatorCommon.SlotHistory, error) {
+	histories := []aggregatorCommon.SlotHistory{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(slotsBucket).Cursor()
+		for key, value := cursor.Seek(slotKey(fromSlot)); key != nil && binary.BigEndian.Uint64(key) <= toSlot; key, value = cursor.Next() {
+			var history aggregatorCommon.SlotHistory
+			if err := json.Unmarshal(value, &history); err != nil {
+				return err
+			}
+			histories = append(histories, history)
+		}
+		return nil
+	})
+	return histories, err
+}
+
+func get(bucket *bolt.Bucket, slot uint64) (aggregatorCommon.SlotHistory, error) {
+	var history aggregatorCommon.SlotHistory
+	value := bucket.Get(slotKey(slot))
+	if value == nil {
+		return history, ErrSlotNotFound
+	}
+	err := json.Unmarshal(value, &history)
+	return history, err
+}
+
+func put(bucket *bolt.Bucket, history aggregatorCommon.SlotHistory) error {
+	value, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(slotKey(history.Slot), value)
+}
+
+// prune deletes the history of slots more than retention slots before the latest slot, keeping all if retention is 0
+func prune(bucket *bolt.Bucket, latestSlot, retention uint64) error {
+	if retention == 0 || latestSlot < retention {
+		return nil
+	}
+
+	// Keys are collected first as deleting while iterating a cursor skips keys
+	var expired [][]byte
+	cursor := bucket.Cursor()
+	for key, _ := cursor.First(); key != nil && binary.BigEndian.Uint64(key) < latestSlot-retention; key, _ = cursor.Next() {
+		expired = append(expired, append([]byte{}, key...))
+	}
+	for _, key := range expired {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slotKey is the big endian slot, so keys are ordered by slot
+func slotKey(slot uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, slot)
+	return key
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := Open(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bids := []aggregatorCommon.BidRecord{
+		{Module: "relay", BlockHash: "0x01", Value: "100", ReceivedAt: 1},
+		{Module: "builder", BlockHash: "0x02", Value: "200", ReceivedAt: 2},
+	}
+	if err := store.RecordAuction(5, "0xparent", "0xproposer", bids, &bids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordDelivery(5, aggregatorCommon.PayloadDelivery{Module: "builder", BlockHash: "0x02", Delivered: true}); err != nil {
+		t.Fatal(err)
+	}
+	// A delivery can be recorded for a slot without an auction
+	if err := store.RecordDelivery(6, aggregatorCommon.PayloadDelivery{Module: "relay", Error: "payload withheld"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// History is kept across restarts
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = Open(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	history, err := store.Slot(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Bids) != 2 || history.Selected == nil || history.Selected.Module != "builder" || history.Delivery == nil || !history.Delivery.Delivered || history.ProposerPubkey != "0xproposer" {
+		t.Errorf("Unexpected slot history %+v", history)
+	}
+	if _, err := store.Slot(7); !errors.Is(err, ErrSlotNotFound) {
+		t.Errorf("Expected no history for the slot, got %v", err)
+	}
+
+	histories, err := store.Range(0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(histories) != 2 || histories[0].Slot != 5 || histories[1].Slot != 6 {
+		t.Errorf("Expected the histories ordered by slot, got %+v", histories)
+	}
+
+	// Recording an auction prunes the slots past retention
+	if err := store.RecordAuction(16, "0xparent", "0xproposer", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	histories, err = store.Range(0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(histories) != 2 || histories[0].Slot != 6 || histories[1].Slot != 16 {
+		t.Errorf("Expected slots more than the retention before the latest to be pruned, got %+v", histories)
+	}
+}
@@ -0,0 +1,165 @@
+package blockaggregator
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-builder-client/spec"
+	consensusspec "github.com/attestantio/go-eth2-client/spec"
+	params "github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	errFeeRecipientMismatch = errors.New("bid fee recipient does not match the proposer registration")
+	errGasLimitMismatch     = errors.New("bid gas limit does not follow the proposer registration")
+)
+
+// registrationViolations counts the bids refused for not matching the proposer registration, by block source
+var registrationViolations = expvar.NewMap("blockAggregator.registrationViolations")
+
+// validatorRegistrations keeps the latest registration of each proposer
+type validatorRegistrations struct {
+	mu            sync.RWMutex
+	registrations map[string]*apiv1.ValidatorRegistration
+}
+
+func newValidatorRegistrations() *validatorRegistrations {
+	return &validatorRegistrations{registrations: make(map[string]*apiv1.ValidatorRegistration)}
+}
+
+// store records the registrations, keeping an earlier one if it has a later timestamp
+func (v *validatorRegistrations) store(payload []apiv1.SignedValidatorRegistration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, registration := range payload {
+		if registration.Message == nil {
+			continue
+		}
+		pubkey := strings.ToLower(registration.Message.Pubkey.String())
+		if previous, ok := v.registrations[pubkey]; ok && previous.Timestamp.After(registration.Message.Timestamp) {
+			continue
+		}
+		v.registrations[pubkey] = registration.Message
+	}
+}
+
+func (v *validatorRegistrations) get(pubkey string) (*apiv1.ValidatorRegistration, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	registration, ok := v.registrations[strings.ToLower(pubkey)]
+	return registration, ok
+}
+
+// checkRegistration returns an error if the bid does not pay the fee recipient the proposer registered, or
+// its gas limit does not move towards the registered gas limit from its parent. The gas limit is only checked
+// when the gas limit of the parent is known, from a header of the aggregator or the execution node.
+func (b *BlockAggregatorService) checkRegistration(proposerPubkey string, bid spec.VersionedSignedBuilderBid) error {
+	registration, ok := b.registrations.get(proposerPubkey)
+	if !ok {
+		return nil
+	}
+
+	feeRecipient, err := bid.FeeRecipient()
+	if err != nil {
+		return err
+	}
+	if feeRecipient != registration.FeeRecipient {
+		return fmt.Errorf("%w: expected %s, got %s", errFeeRecipientMismatch, registration.FeeRecipient, feeRecipient)
+	}
+
+	parentHash, err := bid.ParentHash()
+	if err != nil {
+		return err
+	}
+	parentGasLimit, ok := b.parentGasLimit(parentHash.String())
+	if !ok {
+		return nil
+	}
+	gasLimit, err := bidGasLimit(bid)
+	if err != nil {
+		return err
+	}
+	if expected := calcGasLimit(parentGasLimit, registration.GasLimit); gasLimit != expected {
+		return fmt.Errorf("%w: expected %d, got %d", errGasLimitMismatch, expected, gasLimit)
+	}
+
+	return nil
+}
+
+// parentGasLimit returns the gas limit of the parent block, from the headers of the aggregator or else
+// the execution node if one is set
+func (b *BlockAggregatorService) parentGasLimit(parentHash string) (uint64, bool) {
+	if parent, err := b.Data.GetSlotHeaderByHash(parentHash); err == nil && parent.Bid != nil {
+		if gasLimit, err := bidGasLimit(*parent.Bid); err == nil {
+			return gasLimit, true
+		}
+	}
+	if b.executionClient == nil {
+		return 0, false
+	}
+	gasLimit, err := b.executionClient.gasLimit(parentHash)
+	if err != nil {
+		b.log.WithError(err).WithField("parentHash", parentHash).Warn("could not get the parent gas limit from the execution node")
+		return 0, false
+	}
+	return gasLimit, true
+}
+
+// bidGasLimit returns the gas limit of the execution payload header of the bid
+func bidGasLimit(bid spec.VersionedSignedBuilderBid) (uint64, error) {
+	if bid.IsEmpty() {
+		return 0, errors.New("no bid")
+	}
+	switch bid.Version {
+	case consensusspec.DataVersionBellatrix:
+		if bid.Bellatrix.Message == nil || bid.Bellatrix.Message.Header == nil {
+			return 0, errors.New("no header")
+		}
+		return bid.Bellatrix.Message.Header.GasLimit, nil
+	case consensusspec.DataVersionCapella:
+		if bid.Capella.Message == nil || bid.Capella.Message.Header == nil {
+			return 0, errors.New("no header")
+		}
+		return bid.Capella.Message.Header.GasLimit, nil
+	case consensusspec.DataVersionDeneb:
+		if bid.Deneb.Message == nil || bid.Deneb.Message.Header == nil {
+			return 0, errors.New("no header")
+		}
+		return bid.Deneb.Message.Header.GasLimit, nil
+	default:
+		return 0, errors.New("unsupported version")
+	}
+}
+
+// calcGasLimit is the gas limit of the block after the parent, moving towards the desired limit
+// by at most the parent gas limit over the bound divisor, as computed by execution clients
+func calcGasLimit(parentGasLimit, desiredLimit uint64) uint64 {
+	delta := parentGasLimit/params.GasLimitBoundDivisor - 1
+	if desiredLimit < params.MinGasLimit {
+		desiredLimit = params.MinGasLimit
+	}
+
+	if parentGasLimit < desiredLimit {
+		if limit := parentGasLimit + delta; limit < desiredLimit {
+			return limit
+		}
+		return desiredLimit
+	}
+	if parentGasLimit > desiredLimit {
+		if limit := parentGasLimit - delta; limit > desiredLimit {
+			return limit
+		}
+		return desiredLimit
+	}
+	return parentGasLimit
+}
@@ -0,0 +1,160 @@
+package blockaggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/proposer"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/config"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
+)
+
+func TestCheckRegistration(t *testing.T) {
+	b := NewBlockAggregatorService()
+
+	var pubkey phase0.BLSPubKey
+	if err := pubkey.UnmarshalJSON([]byte(`"` + testPubkey + `"`)); err != nil {
+		t.Fatal(err)
+	}
+	register := func(feeRecipient byte, gasLimit uint64, timestamp time.Time) apiv1.SignedValidatorRegistration {
+		return apiv1.SignedValidatorRegistration{Message: &apiv1.ValidatorRegistration{
+			FeeRecipient: bellatrix.ExecutionAddress{feeRecipient},
+			GasLimit:     gasLimit,
+			Timestamp:    timestamp,
+			Pubkey:       pubkey,
+		}}
+	}
+	newBid := func(blockHash, parentHash, feeRecipient byte, gasLimit uint64) data.SlotHeader {
+		bid := testAuctionBid(blockHash, 10)
+		bid.Capella.Message.Header.ParentHash = phase0.Hash32{parentHash}
+		bid.Capella.Message.Header.FeeRecipient = bellatrix.ExecutionAddress{feeRecipient}
+		bid.Capella.Message.Header.GasLimit = gasLimit
+		hash, _ := bid.BlockHash()
+		return data.SlotHeader{Slot: 100, Bid: &bid, BlockHash: hash.String()}
+	}
+
+	if err := b.checkRegistration(testPubkey, *newBid(1, 0, 9, 30_000_000).Bid); err != nil {
+		t.Errorf("Expected bids for proposers without a registration to be accepted, got %v", err)
+	}
+
+	// An older registration does not replace the latest one
+	now := time.Now()
+	b.registrations.store([]apiv1.SignedValidatorRegistration{register(1, 36_000_000, now), register(2, 30_000_000, now.Add(-time.Minute))})
+
+	if err := b.checkRegistration(testPubkey, *newBid(1, 0, 2, 30_000_000).Bid); !errors.Is(err, errFeeRecipientMismatch) {
+		t.Errorf("Expected a fee recipient mismatch, got %v", err)
+	}
+	if err := b.checkRegistration(testPubkey, *newBid(1, 0, 1, 30_000_000).Bid); err != nil {
+		t.Errorf("Expected a bid paying the registered fee recipient to be accepted, got %v", err)
+	}
+
+	// The gas limit is not checked while the parent is unknown
+	if err := b.checkRegistration(testPubkey, *newBid(6, 8, 1, 36_000_000).Bid); err != nil {
+		t.Errorf("Expected the gas limit not to be checked without a known parent, got %v", err)
+	}
+
+	parent := newBid(5, 0, 1, 30_000_000)
+	if err := b.Data.AddSlotHeader(parent); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.checkRegistration(testPubkey, *newBid(6, 5, 1, 36_000_000).Bid); !errors.Is(err, errGasLimitMismatch) {
+		t.Errorf("Expected a gas limit moving faster than allowed from the parent to be refused, got %v", err)
+	}
+	if err := b.checkRegistration(testPubkey, *newBid(6, 5, 1, 30_000_000-30_000_000/1024+1).Bid); !errors.Is(err, errGasLimitMismatch) {
+		t.Errorf("Expected a gas limit moving away from the registration to be refused, got %v", err)
+	}
+	if err := b.checkRegistration(testPubkey, *newBid(6, 5, 1, 30_000_000+30_000_000/1024-1).Bid); err != nil {
+		t.Errorf("Expected a gas limit moving towards the registration to be accepted, got %v", err)
+	}
+
+	// Violating bids are not kept for the slot
+	if err := b.processNewBid("relay", 101, testPubkey, *newBid(7, 0, 2, 30_000_000).Bid, proposer.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Data.GetSelectedSlotHeaders(101); err == nil {
+		t.Error("Expected the bid paying another fee recipient to be refused")
+	}
+	if violations := registrationViolations.Get("relay"); violations == nil || violations.String() != "1" {
+		t.Errorf("Expected the violation to be counted for the block source, got %v", violations)
+	}
+}
+
+func TestRegistrationWithoutMessage(t *testing.T) {
+	b := NewBlockAggregatorService()
+
+	err := b.processValidatorRegistrations([]apiv1.SignedValidatorRegistration{{}})
+	var invalidParams *common.InvalidParamsError
+	if !errors.As(err, &invalidParams) {
+		t.Errorf("Expected a registration without a message to be invalid, got %v", err)
+	}
+}
+
+func TestCheckRegistrationExecutionNode(t *testing.T) {
+	var calls atomic.Int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "eth_getBlockByHash" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		calls.Add(1)
+		if req.Params[0] != (phase0.Hash32{8}).String() {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"gasLimit":"0x1c9c380"}}`))
+	}))
+	defer node.Close()
+
+	b := NewBlockAggregatorService()
+	if err := b.Configure(common.ModuleFlags{config.ExecutionNodeURLFlag.Name: node.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	var pubkey phase0.BLSPubKey
+	if err := pubkey.UnmarshalJSON([]byte(`"` + testPubkey + `"`)); err != nil {
+		t.Fatal(err)
+	}
+	b.registrations.store([]apiv1.SignedValidatorRegistration{{Message: &apiv1.ValidatorRegistration{
+		FeeRecipient: bellatrix.ExecutionAddress{1},
+		GasLimit:     36_000_000,
+		Timestamp:    time.Now(),
+		Pubkey:       pubkey,
+	}}})
+	newBid := func(parentHash byte, gasLimit uint64) spec.VersionedSignedBuilderBid {
+		bid := testAuctionBid(6, 10)
+		bid.Capella.Message.Header.ParentHash = phase0.Hash32{parentHash}
+		bid.Capella.Message.Header.FeeRecipient = bellatrix.ExecutionAddress{1}
+		bid.Capella.Message.Header.GasLimit = gasLimit
+		return bid
+	}
+
+	// The parent gas limit of 30M is read from the execution node
+	if err := b.checkRegistration(testPubkey, newBid(8, 36_000_000)); !errors.Is(err, errGasLimitMismatch) {
+		t.Errorf("Expected a mismatched gas limit to be refused, got %v", err)
+	}
+	if err := b.checkRegistration(testPubkey, newBid(8, 30_000_000+30_000_000/1024-1)); err != nil {
+		t.Errorf("Expected a gas limit moving towards the registration to be accepted, got %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected the parent gas limit to be read once, got %d calls", n)
+	}
+
+	// Parents unknown to the execution node are not checked
+	if err := b.checkRegistration(testPubkey, newBid(9, 36_000_000)); err != nil {
+		t.Errorf("Expected the gas limit not to be checked for an unknown parent, got %v", err)
+	}
+}
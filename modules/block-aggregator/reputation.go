@@ -0,0 +1,202 @@
+package blockaggregator
+
+import (
+	"expvar"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+)
+
+const (
+	// reputationModeOff tracks the reputation of block sources without using it in selection
+	reputationModeOff = "off"
+	// reputationModeDiscount ranks bids by their value multiplied by the reputation score of their block source
+	reputationModeDiscount = "discount"
+	// reputationModeExclude drops the bids of block sources with a reputation score below the minimum
+	reputationModeExclude = "exclude"
+
+	// reputationWeight is the weight of the latest payload outcome in the reputation score
+	reputationWeight = 0.1
+)
+
+// reputationMetrics publishes the reputation of each block source with expvar
+var reputationMetrics = expvar.NewMap("blockAggregator.reputation")
+
+// sourceReputation is the payload delivery history of a block source
+type sourceReputation struct {
+	score             float64 // moving average of payload outcomes, 1 for delivered and 0 for failed
+	lastOutcome       time.Time
+	headersWon        uint64
+	payloadsDelivered uint64
+	payloadFailures   uint64
+	payloadLatency    time.Duration // moving average of payload request latency
+	lastFailure       string
+
+	metrics *expvar.Map
+}
+
+// reputations tracks how often the winning headers of each block source led to a delivered payload
+type reputations struct {
+	mu      sync.Mutex
+	sources map[string]*sourceReputation
+
+	// recoveryHalfLife is the time without payload outcomes over which a score recovers half of its gap to 1,
+	// so block sources excluded for a low score get bids selected again. 0 disables recovery.
+	recoveryHalfLife time.Duration
+}
+
+func newReputations(recoveryHalfLife time.Duration) *reputations {
+	return &reputations{sources: make(map[string]*sourceReputation), recoveryHalfLife: recoveryHalfLife}
+}
+
+func (r *reputations) setRecoveryHalfLife(recoveryHalfLife time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recoveryHalfLife = recoveryHalfLife
+}
+
+func (r *reputations) get(module string) *sourceReputation {
+	source, ok := r.sources[module]
+	if !ok {
+		source = &sourceReputation{score: 1, metrics: new(expvar.Map).Init()}
+		r.sources[module] = source
+		reputationMetrics.Set(module, source.metrics)
+	}
+	return source
+}
+
+// headerWon records that a header of the block source was selected for a slot
+func (r *reputations) headerWon(module string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source := r.get(module)
+	source.headersWon++
+	source.publish()
+}
+
+// payloadResult records the outcome of requesting the payload of a winning header from the block source
+func (r *reputations) payloadResult(module string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source := r.get(module)
+	outcome := 1.0
+	if err != nil {
+		outcome = 0
+		source.payloadFailures++
+		source.lastFailure = err.Error()
+	} else {
+		source.payloadsDelivered++
+	}
+	now := time.Now()
+	source.score = (1-reputationWeight)*r.recovered(source, now) + reputationWeight*outcome
+	source.lastOutcome = now
+
+	if source.payloadsDelivered+source.payloadFailures == 1 {
+		source.payloadLatency = latency
+	} else {
+		source.payloadLatency = time.Duration((1-reputationWeight)*float64(source.payloadLatency) + reputationWeight*float64(latency))
+	}
+
+	source.publish()
+}
+
+// score returns the reputation score of the block source, from 0 to 1. Block sources without history score 1.
+func (r *reputations) score(module string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, ok := r.sources[module]
+	if !ok {
+		return 1
+	}
+	return r.recovered(source, time.Now())
+}
+
+// recovered returns the score of the block source recovered towards 1 for the time since its last payload outcome
+func (r *reputations) recovered(source *sourceReputation, now time.Time) float64 {
+	if r.recoveryHalfLife <= 0 || source.lastOutcome.IsZero() || source.score >= 1 {
+		return source.score
+	}
+	halfLives := float64(now.Sub(source.lastOutcome)) / float64(r.recoveryHalfLife)
+	if halfLives <= 0 {
+		return source.score
+	}
+	return 1 - (1-source.score)*math.Pow(0.5, halfLives)
+}
+
+func (r *reputations) status() []aggregatorCommon.SourceReputation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]aggregatorCommon.SourceReputation, 0, len(r.sources))
+	for module, source := range r.sources {
+		statuses = append(statuses, aggregatorCommon.SourceReputation{
+			Module:            module,
+			Score:             r.recovered(source, now),
+			HeadersWon:        source.headersWon,
+			PayloadsDelivered: source.payloadsDelivered,
+			PayloadFailures:   source.payloadFailures,
+			PayloadLatencyMs:  source.payloadLatency.Milliseconds(),
+			LastFailure:       source.lastFailure,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Module < statuses[j].Module })
+
+	return statuses
+}
+
+func (s *sourceReputation) publish() {
+	score := new(expvar.Float)
+	score.Set(s.score)
+	s.metrics.Set("score", score)
+	s.metrics.Set("headers_won", expvarInt(s.headersWon))
+	s.metrics.Set("payloads_delivered", expvarInt(s.payloadsDelivered))
+	s.metrics.Set("payload_failures", expvarInt(s.payloadFailures))
+	s.metrics.Set("payload_latency_ms", expvarInt(uint64(s.payloadLatency.Milliseconds())))
+}
+
+func expvarInt(value uint64) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(int64(value))
+	return v
+}
+
+// reputationAllows returns whether bids of the block source can be selected under the reputation mode
+func (b *BlockAggregatorService) reputationAllows(module string) bool {
+	if b.cfg.ReputationMode != reputationModeExclude {
+		return true
+	}
+	return b.reputations.score(module) >= b.cfg.ReputationMinScore
+}
+
+// reputationSelectionValue returns the value a bid of the block source is ranked by under the reputation mode,
+// nil to rank by the bid value
+func (b *BlockAggregatorService) reputationSelectionValue(module string, value *big.Int) *big.Int {
+	if b.cfg.ReputationMode != reputationModeDiscount {
+		return nil
+	}
+	discounted, _ := new(big.Float).Mul(new(big.Float).SetInt(value), big.NewFloat(b.reputations.score(module))).Int(nil)
+	return discounted
+}
+
+func parseReputationMode(mode string) (string, error) {
+	switch mode {
+	case reputationModeOff, reputationModeDiscount, reputationModeExclude:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid reputation mode %s, expected %s, %s or %s", mode, reputationModeOff, reputationModeDiscount, reputationModeExclude)
+	}
+}
+
+// SourceReputations returns the payload delivery history and reputation score of the block sources
+func (b *BlockAggregatorService) SourceReputations() ([]aggregatorCommon.SourceReputation, error) {
+	return b.reputations.status(), nil
+}
@@ -0,0 +1,110 @@
+package blockaggregator
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/proposer"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/config"
+)
+
+func TestReputation(t *testing.T) {
+	b := NewBlockAggregatorService()
+
+	b.reputations.headerWon("relay")
+	b.reputations.payloadResult("relay", 100*time.Millisecond, nil)
+	for i := 0; i < 10; i++ {
+		b.reputations.headerWon("builder")
+		b.reputations.payloadResult("builder", time.Second, errors.New("payload withheld"))
+	}
+
+	if score := b.reputations.score("relay"); score != 1 {
+		t.Errorf("Expected a block source that delivered to keep a score of 1, got %f", score)
+	}
+	if score := b.reputations.score("unknown"); score != 1 {
+		t.Errorf("Expected a block source without history to score 1, got %f", score)
+	}
+	builderScore := b.reputations.score("builder")
+	if builderScore >= 0.5 {
+		t.Errorf("Expected a block source withholding payloads to lose reputation, got %f", builderScore)
+	}
+
+	status := b.reputations.status()
+	if len(status) != 2 || status[0].Module != "builder" || status[0].PayloadFailures != 10 || status[0].HeadersWon != 10 || status[0].LastFailure != "payload withheld" {
+		t.Errorf("Unexpected reputation status %+v", status)
+	}
+
+	t.Run("Off", func(t *testing.T) {
+		b.cfg.ReputationMode = reputationModeOff
+		if !b.reputationAllows("builder") || b.reputationSelectionValue("builder", big.NewInt(100)) != nil {
+			t.Error("Expected reputation not to affect selection when off")
+		}
+	})
+
+	t.Run("Discount", func(t *testing.T) {
+		b.cfg.ReputationMode = reputationModeDiscount
+		if value := b.reputationSelectionValue("builder", big.NewInt(1000)); value.Cmp(big.NewInt(int64(1000*builderScore)+1)) > 0 {
+			t.Errorf("Expected the bid value to be discounted by the reputation score, got %s", value)
+		}
+	})
+
+	t.Run("Exclude", func(t *testing.T) {
+		b.cfg.ReputationMode = reputationModeExclude
+		b.cfg.ReputationMinScore = 0.5
+		bids := []sourceBid{{module: "relay", response: testAuctionBid(1, 10)}, {module: "builder", response: testAuctionBid(2, 20)}}
+		selected := b.selectBids(bids, proposer.Options{})
+		if len(selected) != 1 || selected[0].module != "relay" {
+			t.Errorf("Expected bids of block sources below the minimum score to be dropped, got %+v", selected)
+		}
+	})
+}
+
+func TestReputationRecovery(t *testing.T) {
+	b := NewBlockAggregatorService()
+	if err := b.Configure(common.ModuleFlags{
+		config.ReputationModeFlag.Name:             reputationModeExclude,
+		config.ReputationRecoveryHalfLifeFlag.Name: "60000",
+		config.ReputationMinScoreFlag.Name:         "0.5",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		b.reputations.payloadResult("builder", time.Second, errors.New("payload withheld"))
+	}
+	score := b.reputations.score("builder")
+	if b.reputationAllows("builder") {
+		t.Fatalf("Expected the block source to be excluded with a score of %f", score)
+	}
+
+	// Without payload outcomes the score recovers half of its gap to 1 every half life
+	b.reputations.sources["builder"].lastOutcome = time.Now().Add(-time.Minute)
+	if recovered := b.reputations.score("builder"); math.Abs(recovered-(1-(1-score)/2)) > 0.001 {
+		t.Errorf("Expected the score to recover half of its gap to 1 after a half life, got %f from %f", recovered, score)
+	}
+	b.reputations.sources["builder"].lastOutcome = time.Now().Add(-5 * time.Minute)
+	if !b.reputationAllows("builder") {
+		t.Errorf("Expected the block source to be selected again after recovering, got a score of %f", b.reputations.score("builder"))
+	}
+
+	// A failure after recovering starts from the recovered score
+	recovered := b.reputations.score("builder")
+	b.reputations.payloadResult("builder", time.Second, errors.New("payload withheld"))
+	if score := b.reputations.score("builder"); math.Abs(score-(1-reputationWeight)*recovered) > 0.001 {
+		t.Errorf("Expected the failure to lower the recovered score, got %f from %f", score, recovered)
+	}
+
+	// Recovery can be disabled
+	if err := b.Configure(common.ModuleFlags{config.ReputationRecoveryHalfLifeFlag.Name: "0"}); err != nil {
+		t.Fatal(err)
+	}
+	score = b.reputations.score("builder")
+	b.reputations.sources["builder"].lastOutcome = time.Now().Add(-time.Hour)
+	if b.reputations.score("builder") != score {
+		t.Error("Expected the score not to recover with recovery disabled")
+	}
+}
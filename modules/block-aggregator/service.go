@@ -3,14 +3,20 @@ package blockaggregator
 import (
 	"fmt"
 	"math/big"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/attestantio/go-builder-client/spec"
 	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
+	"github.com/pon-network/mev-plus/common/proposer"
 	coreCommon "github.com/pon-network/mev-plus/core/common"
 	"github.com/pon-network/mev-plus/modules/block-aggregator/config"
 	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/history"
 	"github.com/urfave/cli/v2"
 
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
@@ -28,17 +34,38 @@ type BlockAggregatorService struct {
 	ConnectedBLockSources        []string
 	ModuleNotificationExclusions []string
 	lock                         sync.Mutex
+	headerRequests               *headerRequests
+	equivocationGuard            *equivocationGuard
+	proposerConfig               *proposer.Config
+	auctions                     map[headerRequestKey]*auction
+	auctionsLock                 sync.Mutex
+	circuitBreakers              *circuitBreakers
+	reputations                  *reputations
+	registrations                *validatorRegistrations
+	history                      *history.Store
+	network                      *network.Network
+	executionClient              *executionClient
+	slotStart                    func(slot uint64) (time.Time, bool)
 
 	cfg config.BlockAggregatorConfig
 }
 
 func NewBlockAggregatorService() *BlockAggregatorService {
-	return &BlockAggregatorService{
+	b := &BlockAggregatorService{
 		log:  logrus.NewEntry(logrus.New()).WithField("moduleExecution", config.ModuleName),
 		Data: data.NewAggregatorData(),
 		cfg:  config.BlockAggregatorConfigDefaults,
 		ModuleNotificationExclusions: []string{"builderApi", "blockAggregator"},
+		auctions:                     make(map[headerRequestKey]*auction),
+		reputations:                  newReputations(time.Duration(config.BlockAggregatorConfigDefaults.ReputationRecoveryHalfLife) * time.Millisecond),
+		registrations:                newValidatorRegistrations(),
 	}
+	b.slotStart = b.chainClockSlotStart
+	b.headerRequests = newHeaderRequests(b.headerCacheExpiry)
+	b.circuitBreakers = b.newCircuitBreakers()
+	// Records are only kept in memory until the configured data directory is loaded on start
+	b.equivocationGuard, _ = newEquivocationGuard("")
+	return b
 }
 
 func (b *BlockAggregatorService) CliCommand() *cli.Command {
@@ -50,10 +77,30 @@ func (b *BlockAggregatorService) Name() string {
 }
 
 func (b *BlockAggregatorService) Start() error {
+	// Unblinded block records kept only in memory would let a restart unblind a conflicting block
+	if b.cfg.DataDir == "" {
+		return errNoDataDir
+	}
+
+	guard, err := newEquivocationGuard(b.cfg.DataDir)
+	if err != nil {
+		return fmt.Errorf("failed to load unblinded block records: %w", err)
+	}
+	b.equivocationGuard = guard
+
+	store, err := history.Open(filepath.Join(b.cfg.DataDir, "history.db"), b.cfg.HistoryRetention)
+	if err != nil {
+		return err
+	}
+	b.history = store
+
 	return nil
 }
 
 func (b *BlockAggregatorService) Stop() error {
+	if b.history != nil {
+		return b.history.Close()
+	}
 	return nil
 }
 
@@ -75,27 +122,147 @@ func (b *BlockAggregatorService) Configure(moduleFlags common.ModuleFlags) error
 
 	for flagName, flagValue := range moduleFlags {
 		switch flagName {
+		case config.NetworkFlag.Name:
+			// the fork schedule bids are checked against, slot times are kept by the chain clock module
+			n, err := network.Load(flagValue)
+			if err != nil {
+				return fmt.Errorf("-%s: %w", config.NetworkFlag.Name, err)
+			}
+			b.network = n
 		case config.AuctionDurationFlag.Name:
 			flagValint, err := strconv.Atoi(flagValue)
 			if err != nil {
 				return err
 			}
 			b.cfg.AuctionDuration = uint64(flagValint)
-		case config.SlotDurationFlag.Name:
+		case config.AuctionPollIntervalFlag.Name:
 			flagValint, err := strconv.Atoi(flagValue)
 			if err != nil {
 				return err
 			}
-			b.cfg.SlotDuration = uint64(flagValint)
-		case config.GenesisTimeFlag.Name:
+			b.cfg.AuctionPollInterval = uint64(flagValint)
+		case config.AuctionValueThresholdFlag.Name:
+			threshold, ok := new(big.Int).SetString(flagValue, 10)
+			if !ok || threshold.Sign() < 0 {
+				return fmt.Errorf("invalid auction value threshold %s", flagValue)
+			}
+			if threshold.Sign() == 0 {
+				threshold = nil
+			}
+			b.cfg.AuctionValueThreshold = threshold
+		case config.AuctionMaxLatencyFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.AuctionMaxLatency = uint64(flagValint)
+		case config.SourceTimeoutFlag.Name:
 			flagValint, err := strconv.Atoi(flagValue)
 			if err != nil {
 				return err
 			}
-			b.cfg.GenesisTime = uint64(flagValint)
+			b.cfg.SourceTimeout = uint64(flagValint)
+		case config.SourceTimeoutsFlag.Name:
+			sourceTimeouts, err := parseSourceTimeouts(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.SourceTimeouts = sourceTimeouts
+		case config.BreakerFailureThresholdFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.BreakerFailureThreshold = uint64(flagValint)
+		case config.BreakerLatencyThresholdFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.BreakerLatencyThreshold = uint64(flagValint)
+		case config.BreakerOpenDurationFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.BreakerOpenDuration = uint64(flagValint)
+		case config.HeaderCacheDurationFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.HeaderCacheDuration = uint64(flagValint)
+		case config.ReputationModeFlag.Name:
+			mode, err := parseReputationMode(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.ReputationMode = mode
+		case config.ReputationMinScoreFlag.Name:
+			minScore, err := strconv.ParseFloat(flagValue, 64)
+			if err != nil {
+				return err
+			}
+			if minScore < 0 || minScore > 1 {
+				return fmt.Errorf("invalid reputation min score %s, expected a value from 0 to 1", flagValue)
+			}
+			b.cfg.ReputationMinScore = minScore
+		case config.ReputationRecoveryHalfLifeFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			if flagValint < 0 {
+				return fmt.Errorf("invalid reputation recovery half life %s", flagValue)
+			}
+			b.cfg.ReputationRecoveryHalfLife = uint64(flagValint)
+		case config.BuilderAllowlistFlag.Name:
+			builders, err := parseBuilderPubkeys(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.BuilderAllowlist = builders
+		case config.BuilderDenylistFlag.Name:
+			builders, err := parseBuilderPubkeys(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.BuilderDenylist = builders
+		case config.DataDirFlag.Name:
+			b.cfg.DataDir = flagValue
+		case config.ExecutionNodeURLFlag.Name:
+			if flagValue == "" {
+				continue
+			}
+			if _, err := url.ParseRequestURI(flagValue); err != nil {
+				return fmt.Errorf("-%s: %w", config.ExecutionNodeURLFlag.Name, err)
+			}
+			b.cfg.ExecutionNodeURL = flagValue
+		case config.HistoryRetentionFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.HistoryRetention = uint64(flagValint)
+		case config.ProposerConfigFlag.Name:
+			if flagValue == "" {
+				continue
+			}
+			proposerConfig, err := proposer.Load(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.ProposerConfig = flagValue
+			b.proposerConfig = proposerConfig
 		}
 	}
 
+	b.circuitBreakers = b.newCircuitBreakers()
+	b.reputations.setRecoveryHalfLife(time.Duration(b.cfg.ReputationRecoveryHalfLife) * time.Millisecond)
+	if b.cfg.ExecutionNodeURL != "" {
+		b.executionClient = newExecutionClient(b.cfg.ExecutionNodeURL, time.Duration(b.cfg.SourceTimeout)*time.Millisecond)
+	}
+
 	return nil
 }
 
@@ -206,6 +373,12 @@ func (b *BlockAggregatorService) IncludeInNotifications(moduleName string) error
 	return nil
 }
 
+// ProposerConfig returns the proposer config so block sources can apply the per validator settings,
+// nil if no proposer config is set
+func (b *BlockAggregatorService) ProposerConfig() (*proposer.Config, error) {
+	return b.proposerConfig, nil
+}
+
 func (b *BlockAggregatorService) Status() error {
 	b.log.Info("Checking status of block aggregator and connected block sources")
 	return b.checkBlockSources()
@@ -229,10 +402,10 @@ func (b *BlockAggregatorService) GetHeader(slot uint64, parentHash, proposerPubk
 			"proposerPubkey": proposerPubkey,
 			"parentHash":     parentHash,
 		}).Error("invalid proposerPubkey or parentHash")
-		return res, fmt.Errorf("invalid proposerPubkey or parentHash")
+		return res, &common.InvalidParamsError{Message: "invalid proposerPubkey or parentHash"}
 	}
 
-	slotHeader, err := b.processHeaderReq(slot, parentHash, proposerPubkey)
+	slotHeader, err := b.headerRequests.do(slot, parentHash, proposerPubkey, b.processHeaderReq)
 	if err != nil {
 		b.log.WithError(err).WithFields(logrus.Fields{
 			"slot":           slot,
@@ -262,7 +435,7 @@ func (b *BlockAggregatorService) GetPayload(VersionedSignedBlindedBeaconBlock *c
 	base, err := VersionedSignedBlindedBeaconBlock.ToBaseSignedBlindedBeaconBlock()
 	if err != nil {
 		b.log.WithError(err).Error("error processing payload request, invalid VersionedSignedBlindedBeaconBlock")
-		return versionedExecutionPayload, err
+		return versionedExecutionPayload, &common.InvalidParamsError{Message: err.Error()}
 	}
 
 	result, slotHeader, err := b.processPayloadReq(*VersionedSignedBlindedBeaconBlock)
@@ -306,3 +479,37 @@ func (b *BlockAggregatorService) GetPayload(VersionedSignedBlindedBeaconBlock *c
 	return versionedExecutionPayload, fmt.Errorf("empty payload returned")
 
 }
+
+// SubmitBlindedBlock submits the signed blinded block to the block source of its header for it to publish the block,
+// returning once the block source confirms delivery without returning the payload
+func (b *BlockAggregatorService) SubmitBlindedBlock(VersionedSignedBlindedBeaconBlock *commonTypes.VersionedSignedBlindedBeaconBlock) error {
+	b.log.Info("Processing blinded block submission through block aggregator")
+
+	base, err := VersionedSignedBlindedBeaconBlock.ToBaseSignedBlindedBeaconBlock()
+	if err != nil {
+		b.log.WithError(err).Error("error processing blinded block submission, invalid VersionedSignedBlindedBeaconBlock")
+		return &common.InvalidParamsError{Message: err.Error()}
+	}
+
+	slotHeader, err := b.processBlindedBlockSubmission(*VersionedSignedBlindedBeaconBlock)
+	if err != nil {
+		b.log.WithError(err).WithFields(logrus.Fields{
+			"slot":          base.Message.Slot,
+			"parentHash":    base.Message.ParentRoot.String(),
+			"proposerIndex": base.Message.ProposerIndex,
+			"blockHash":     base.Message.Body.ExecutionPayloadHeader.BlockHash.String(),
+		}).Error("error processing blinded block submission")
+		return err
+	}
+
+	b.log.WithFields(logrus.Fields{
+		"slot":          base.Message.Slot,
+		"parentHash":    base.Message.ParentRoot.String(),
+		"proposerIndex": base.Message.ProposerIndex,
+		"blockHash":     base.Message.Body.ExecutionPayloadHeader.BlockHash.String(),
+		"value":         big.NewFloat(0).SetInt(slotHeader.Value).Quo(big.NewFloat(0).SetInt(slotHeader.Value), big.NewFloat(0).SetInt(big.NewInt(params.Ether))).String() + " ETH",
+		"fromModule":    slotHeader.ModuleName,
+	}).Info("block aggregator submitted blinded block for publication")
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package blockaggregator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pon-network/mev-plus/common"
+	aggregatorCommon "github.com/pon-network/mev-plus/modules/block-aggregator/common"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/data"
+	"github.com/pon-network/mev-plus/modules/block-aggregator/history"
+	"github.com/sirupsen/logrus"
+)
+
+// maxHistoryRange is the most slots of history returned at once, one day
+const maxHistoryRange = 7200
+
+var errHistoryDisabled = errors.New("slot history is not kept without a data directory")
+
+// newBidRecord returns the history record of a bid received from a block source
+func newBidRecord(bid sourceBid) aggregatorCommon.BidRecord {
+	record := aggregatorCommon.BidRecord{Module: bid.module, ReceivedAt: bid.receivedAt.UnixMilli()}
+	if blockHash, err := bid.response.BlockHash(); err == nil {
+		record.BlockHash = blockHash.String()
+	}
+	if builder, err := bid.response.Builder(); err == nil {
+		record.Builder = builder.String()
+	}
+	if value, err := bid.response.Value(); err == nil {
+		record.Value = value.Dec()
+	}
+	return record
+}
+
+// recordAuction records the bids received in the auction for the slot and the header selected from them
+func (b *BlockAggregatorService) recordAuction(slot uint64, parentHash, proposerPubkey string, bids []sourceBid, selected *data.SlotHeader) {
+	if b.history == nil {
+		return
+	}
+
+	records := make([]aggregatorCommon.BidRecord, 0, len(bids))
+	var selectedRecord *aggregatorCommon.BidRecord
+	for _, bid := range bids {
+		record := newBidRecord(bid)
+		records = append(records, record)
+		if selected != nil && record.BlockHash == selected.BlockHash && bid.module == selected.ModuleName {
+			selectedRecord = &record
+		}
+	}
+
+	if err := b.history.RecordAuction(slot, parentHash, proposerPubkey, records, selectedRecord); err != nil {
+		b.log.WithError(err).WithField("slot", slot).Warn("failed to record auction history")
+	}
+}
+
+// recordDelivery records the outcome of requesting the payload of the selected header from its block source,
+// or of submitting the blinded block for the block source to publish
+func (b *BlockAggregatorService) recordDelivery(slotHeader data.SlotHeader, requestedAt time.Time, latency time.Duration, published bool, err error) {
+	if b.history == nil {
+		return
+	}
+
+	delivery := aggregatorCommon.PayloadDelivery{
+		Module:      slotHeader.ModuleName,
+		BlockHash:   slotHeader.BlockHash,
+		Delivered:   err == nil,
+		Published:   published,
+		LatencyMs:   latency.Milliseconds(),
+		RequestedAt: requestedAt.UnixMilli(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if err := b.history.RecordDelivery(slotHeader.Slot, delivery); err != nil {
+		b.log.WithError(err).WithFields(logrus.Fields{
+			"slot":   slotHeader.Slot,
+			"module": slotHeader.ModuleName,
+		}).Warn("failed to record payload delivery history")
+	}
+}
+
+// SlotHistory returns the bids received, the selected header and the payload delivery outcome of the slot
+func (b *BlockAggregatorService) SlotHistory(slot uint64) (aggregatorCommon.SlotHistory, error) {
+	if b.history == nil {
+		return aggregatorCommon.SlotHistory{}, errHistoryDisabled
+	}
+
+	slotHistory, err := b.history.Slot(slot)
+	if errors.Is(err, history.ErrSlotNotFound) {
+		return slotHistory, &common.NoContentError{Message: err.Error()}
+	}
+	return slotHistory, err
+}
+
+// SlotHistoryRange returns the history of the slots from fromSlot to toSlot inclusive
+func (b *BlockAggregatorService) SlotHistoryRange(fromSlot, toSlot uint64) ([]aggregatorCommon.SlotHistory, error) {
+	if b.history == nil {
+		return nil, errHistoryDisabled
+	}
+	if toSlot < fromSlot || toSlot-fromSlot >= maxHistoryRange {
+		return nil, &common.InvalidParamsError{Message: fmt.Sprintf("slot range must be ordered and at most %d slots", maxHistoryRange)}
+	}
+
+	return b.history.Range(fromSlot, toSlot)
+}
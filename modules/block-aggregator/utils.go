@@ -1,10 +1,16 @@
 package blockaggregator
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/proposer"
 	"github.com/sirupsen/logrus"
 )
 
@@ -29,3 +35,60 @@ func HexToHash(s string) (ret phase0.Hash32, err error) {
 	copy(ret[:], bytes)
 	return
 }
+
+// blindedBlockRoot returns the hash tree root of the message of a signed blinded beacon block
+func blindedBlockRoot(block commonTypes.VersionedSignedBlindedBeaconBlock) (phase0.Root, error) {
+	switch {
+	case block.Deneb != nil && block.Deneb.Message != nil:
+		return block.Deneb.Message.HashTreeRoot()
+	case block.Capella != nil && block.Capella.Message != nil:
+		return block.Capella.Message.HashTreeRoot()
+	case block.Bellatrix != nil && block.Bellatrix.Message != nil:
+		return block.Bellatrix.Message.HashTreeRoot()
+	default:
+		return phase0.Root{}, fmt.Errorf("no blinded beacon block message set")
+	}
+}
+
+// isNoContentError returns whether a module call failed only because there was nothing to return
+func isNoContentError(err error) bool {
+	var rpcErr common.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == common.RPCNoContentErrorCode
+}
+
+// parseSourceTimeouts parses a comma separated list of module=milliseconds
+func parseSourceTimeouts(value string) (map[string]uint64, error) {
+	sourceTimeouts := make(map[string]uint64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		module, timeout, ok := strings.Cut(entry, "=")
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid source timeout %s, expected module=milliseconds", entry)
+		}
+		timeoutMs, err := strconv.ParseUint(timeout, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source timeout %s: %w", entry, err)
+		}
+		sourceTimeouts[module] = timeoutMs
+	}
+	return sourceTimeouts, nil
+}
+
+// parseBuilderPubkeys parses a comma separated list of builder pubkeys
+func parseBuilderPubkeys(value string) ([]string, error) {
+	var pubkeys []string
+	for _, pubkey := range strings.Split(value, ",") {
+		pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+		if pubkey == "" {
+			continue
+		}
+		if !proposer.IsPubkey(pubkey) {
+			return nil, fmt.Errorf("invalid builder pubkey %s", pubkey)
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
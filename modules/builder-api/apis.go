@@ -7,21 +7,26 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
 
 	"github.com/attestantio/go-builder-client/spec"
 
 	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+
+	"github.com/pon-network/mev-plus/common/network"
 )
 
 const (
 	// Router paths
-	pathRoot              = "/"
-	pathStatus            = "/eth/v1/builder/status"
-	pathRegisterValidator = "/eth/v1/builder/validators"
-	pathGetHeader         = "/eth/v1/builder/header/{slot:[0-9]+}/{parent_hash:0x[a-fA-F0-9]+}/{pubkey:0x[a-fA-F0-9]+}"
-	pathGetPayload        = "/eth/v1/builder/blinded_blocks"
+	pathRoot                 = "/"
+	pathOpenAPI              = "/.well-known/openapi.json"
+	pathStatus               = "/eth/v1/builder/status"
+	pathRegisterValidator    = "/eth/v1/builder/validators"
+	pathGetHeader            = "/eth/v1/builder/header/{slot}/{parent_hash}/{pubkey}"
+	pathGetPayload           = "/eth/v1/builder/blinded_blocks"
+	pathSubmitBlindedBlockV2 = "/eth/v2/builder/blinded_blocks"
 )
 
 type httpErrorResp struct {
@@ -62,9 +67,27 @@ func (b *BuilderApiService) handleRegisterValidator(w http.ResponseWriter, req *
 		return
 	}
 
-	err = b.coreClient.Call(nil, "blockAggregator_registerValidator", false, nil, payload)
-	if err != nil {
-		b.respondError(w, http.StatusInternalServerError, err.Error())
+	var failures []registrationFailure
+	if !b.cfg.SkipRegistrationCheck {
+		payload, failures = b.registrationVerifier.verify(payload)
+		for _, failure := range failures {
+			b.log.WithFields(logrus.Fields{
+				"pubkey": failure.Pubkey,
+				"reason": failure.Reason,
+			}).Warn("Rejected validator registration")
+		}
+	}
+
+	if len(payload) > 0 {
+		err = b.coreClient.Call(nil, "blockAggregator_registerValidator", false, nil, payload)
+		if err != nil {
+			b.respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if len(failures) > 0 {
+		b.respondRegistrationFailures(w, len(payload), failures)
 		return
 	}
 	b.respondOK(w, nilResponse)
@@ -82,55 +105,102 @@ func (b *BuilderApiService) handleGetHeader(w http.ResponseWriter, req *http.Req
 		return
 	}
 
+	// Path parameters have been validated against the OpenAPI document
 	pubkey := vars["pubkey"]
-	if len(pubkey) != 98 {
-		b.respondError(w, http.StatusBadRequest, errInvalidPubkey.Error())
-		return
-	}
-
 	parentHash := vars["parent_hash"]
-	if len(parentHash) != 66 {
-		b.respondError(w, http.StatusBadRequest, errInvalidHash.Error())
-		return
-	}
 
 	result := []spec.VersionedSignedBuilderBid{}
 	err = b.coreClient.Call(&result, "blockAggregator_getHeader", false, nil, slot, parentHash, pubkey)
 	if err != nil {
-		b.respondError(w, http.StatusInternalServerError, err.Error())
+		b.respondCallError(w, err)
 		return
 	}
 
-	if len(result) == 0 {
-		b.respondError(w, http.StatusNoContent, "")
-	}
+	b.pendingResponses.add()
+	defer b.pendingResponses.done()
 
-	if result[0].IsEmpty() {
-		b.respondError(w, http.StatusNoContent, "")
+	if len(result) == 0 || result[0].IsEmpty() {
+		b.respondNoContent(w)
 		return
 	}
 
-	b.respondOK(w, &(result[0]))
+	b.respondOKWithVersion(w, result[0].Version.String(), &(result[0]))
 }
 
 func (b *BuilderApiService) handleGetPayload(w http.ResponseWriter, req *http.Request) {
 	// Post call.
-	payload := new(commonTypes.VersionedSignedBlindedBeaconBlock)
-	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
-		b.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+	payload, ok := b.decodeBlindedBlock(w, req)
+	if !ok {
 		return
 	}
 
 	result := []commonTypes.VersionedExecutionPayloadV2WithVersionName{}
 	err := b.coreClient.Call(&result, "blockAggregator_getPayload", false, nil, payload)
 	if err != nil {
-		b.respondError(w, http.StatusInternalServerError, err.Error())
+		b.respondCallError(w, err)
 		return
 	}
 
+	// A payload has been revealed for the slot, shutdown waits for it to reach the proposer
+	b.pendingResponses.add()
+	defer b.pendingResponses.done()
+
 	if len(result) == 0 {
 		b.respondError(w, http.StatusInternalServerError, "blockAggregator returned no payload")
+		return
+	}
+
+	b.respondOKWithVersion(w, result[0].VersionName, &(result[0]))
+}
+
+func (b *BuilderApiService) handleSubmitBlindedBlockV2(w http.ResponseWriter, req *http.Request) {
+	// Post call.
+	payload, ok := b.decodeBlindedBlock(w, req)
+	if !ok {
+		return
+	}
+
+	// The block source publishes the block itself, so no payload is returned to the proposer
+	err := b.coreClient.Call(nil, "blockAggregator_submitBlindedBlock", false, nil, payload)
+	if err != nil {
+		b.respondCallError(w, err)
+		return
+	}
+
+	b.respondAccepted(w)
+}
+
+// decodeBlindedBlock decodes the signed blinded block of the request and checks its version,
+// responding with the error and returning false if it is not valid
+func (b *BuilderApiService) decodeBlindedBlock(w http.ResponseWriter, req *http.Request) (*commonTypes.VersionedSignedBlindedBeaconBlock, bool) {
+	payload := new(commonTypes.VersionedSignedBlindedBeaconBlock)
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		b.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return nil, false
+	}
+
+	blockVersion, err := payload.Version()
+	if err != nil {
+		b.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %v", err))
+		return nil, false
+	}
+
+	// The consensus version header is optional, but if set it must be a fork with builder types in this
+	// build and match the fork of the submitted block
+	version := req.Header.Get(HeaderEthConsensusVersion)
+	if _, err := (network.Fork{Name: version}).DataVersion(); version != "" && err != nil {
+		b.respondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if version != "" && version != blockVersion {
+		b.respondError(w, http.StatusBadRequest, fmt.Sprintf("%v: header %s, block %s", errConsensusVersionMismatch, version, blockVersion))
+		return nil, false
+	}
+
+	if err := b.checkForkVersion(payload, blockVersion); err != nil {
+		b.respondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
 	}
 
-	b.respondOK(w, &(result[0]))
+	return payload, true
 }
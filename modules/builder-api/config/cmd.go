@@ -23,10 +23,15 @@ func builderApiFlags() []cli.Flag {
 		LoggerLevelFlag,
 		LoggerFormatFlag,
 		ListenAddressFlag,
+		MetricsListenAddressFlag,
 		ServerReadTimeoutMsFlag,
 		ServerReadHeaderTimeoutMsFlag,
 		ServerWriteTimeoutMsFlag,
 		ServerIdleTimeoutMsFlag,
 		ServerMaxHeaderBytesFlag,
+		ShutdownTimeoutMsFlag,
+		NetworkFlag,
+		GenesisForkVersionFlag,
+		SkipRegistrationSignatureCheckFlag,
 	}
 }
@@ -7,21 +7,28 @@ import (
 type BuilderApiConfig struct {
 	LoggerLevel               string
 	LoggerFormat              string
-	ListenAddress             *url.URL
+	ListenAddresses           []*url.URL
+	MetricsListenAddresses    []*url.URL
 	ServerReadTimeoutMs       int
 	ServerReadHeaderTimeoutMs int
 	ServerWriteTimeoutMs      int
 	ServerIdleTimeoutMs       int
 	ServerMaxHeaderBytes      int
+	ShutdownTimeoutMs         int
+	GenesisForkVersion        string
+	SkipRegistrationCheck     bool
 }
 
 var BuilderApiConfigDefaults = BuilderApiConfig{
 	LoggerLevel:               "info",
 	LoggerFormat:              "text",
-	ListenAddress:             &url.URL{Scheme: "http", Host: "localhost:18551"},
+	ListenAddresses:           []*url.URL{{Scheme: "http", Host: "localhost:18551"}},
 	ServerReadTimeoutMs:       12000,
 	ServerReadHeaderTimeoutMs: 12000,
 	ServerWriteTimeoutMs:      12000,
 	ServerIdleTimeoutMs:       12000,
 	ServerMaxHeaderBytes:      100000,
+	ShutdownTimeoutMs:         10000,
+	GenesisForkVersion:        "0x00000000",
+	SkipRegistrationCheck:     false,
 }
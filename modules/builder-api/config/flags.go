@@ -15,11 +15,18 @@ var (
 	}
 	ListenAddressFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "listen-address",
-		Usage:    "Set the listen address",
+		Usage:    "Set the listen addresses, comma separated. Supports http://host:port, https://host:port?tls-cert=<file>&tls-key=<file> and unix:///path/to/socket, with basic auth user info or an auth-token query parameter per address",
 		Category: utils.BuilderAPICategory,
 		Value:    "",
 		EnvVars:  []string{"BUILDERAPI_LISTEN_ADDRESS"},
 	}
+	MetricsListenAddressFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "metrics-listen-address",
+		Usage:    "Set the listen addresses of the expvar metrics at /debug/vars, comma separated, in the format of the listen addresses. The metrics are not served if unset",
+		Category: utils.BuilderAPICategory,
+		Value:    "",
+		EnvVars:  []string{"BUILDERAPI_METRICS_LISTEN_ADDRESS"},
+	}
 	LoggerFormatFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "logger-format",
 		Usage:    "Set the logger format",
@@ -67,4 +74,35 @@ var (
 		Value:    4000,
 		EnvVars:  []string{"BUILDERAPI_SERVER_MAX_HEADER_BYTES"},
 	}
+
+	ShutdownTimeoutMsFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "shutdown-timeout-ms",
+		Usage:    "Set how long in milliseconds the server waits for in-flight requests to finish when shutting down",
+		Category: utils.BuilderAPICategory,
+		Value:    BuilderApiConfigDefaults.ShutdownTimeoutMs,
+		EnvVars:  []string{"BUILDERAPI_SHUTDOWN_TIMEOUT_MS"},
+	}
+
+	NetworkFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "network",
+		Usage:    "Set the genesis fork version and fork schedule from a network preset (mainnet, sepolia, goerli, holesky) or a consensus config.yaml path or directory for devnets",
+		Category: utils.BuilderAPICategory,
+		EnvVars:  []string{"BUILDERAPI_NETWORK"},
+	}
+
+	GenesisForkVersionFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "genesis-fork-version",
+		Usage:    "Set the genesis fork version used to verify validator registration signatures",
+		Category: utils.BuilderAPICategory,
+		Value:    BuilderApiConfigDefaults.GenesisForkVersion,
+		EnvVars:  []string{"BUILDERAPI_GENESIS_FORK_VERSION"},
+	}
+
+	SkipRegistrationSignatureCheckFlag = &cli.BoolFlag{
+		Name:     ModuleName + "." + "skip-registration-signature-check",
+		Usage:    "Skip the validator registration signature check",
+		Category: utils.BuilderAPICategory,
+		Value:    false,
+		EnvVars:  []string{"BUILDERAPI_SKIP_REGISTRATION_SIGNATURE_CHECK"},
+	}
 )
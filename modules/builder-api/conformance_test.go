@@ -0,0 +1,372 @@
+package builderapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	builderCapella "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-builder-client/spec"
+	apiv1Capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	consensusspec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	"github.com/holiman/uint256"
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
+	coreCommon "github.com/pon-network/mev-plus/core/common"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	testParentHash = "0x534809bd2b6832edff8d8ce4cb0e50068804fd1ef432c8362ad708a74fdc0e46"
+	testPubkey     = "0x8a1d7b8dd64e0aafe7ea7b6c95065c9364cf99d38470c12ee807d55f7de1529ad29ce2c422e0b65e3d5a05c02caca249"
+)
+
+// fakeAggregator stands in for the block aggregator on the core, answering with canned results
+type fakeAggregator struct {
+	header     []spec.VersionedSignedBuilderBid
+	headerErr  error
+	payload    []commonTypes.VersionedExecutionPayloadV2WithVersionName
+	payloadErr error
+	submitErr  error
+
+	// release, if set, blocks GetPayload until it is closed
+	release chan struct{}
+}
+
+func (f *fakeAggregator) Name() string                                                { return "blockAggregator" }
+func (f *fakeAggregator) Start() error                                                { return nil }
+func (f *fakeAggregator) Stop() error                                                 { return nil }
+func (f *fakeAggregator) ConnectCore(*coreCommon.Client, string) error                { return nil }
+func (f *fakeAggregator) Configure(common.ModuleFlags) error                          { return nil }
+func (f *fakeAggregator) CliCommand() *cli.Command                                    { return nil }
+func (f *fakeAggregator) Status() error                                               { return nil }
+func (f *fakeAggregator) RegisterValidator([]apiv1.SignedValidatorRegistration) error { return nil }
+
+func (f *fakeAggregator) GetHeader(slot uint64, parentHash, proposerPubkey string) ([]spec.VersionedSignedBuilderBid, error) {
+	return f.header, f.headerErr
+}
+
+func (f *fakeAggregator) GetPayload(block *commonTypes.VersionedSignedBlindedBeaconBlock) ([]commonTypes.VersionedExecutionPayloadV2WithVersionName, error) {
+	if f.release != nil {
+		<-f.release
+	}
+	return f.payload, f.payloadErr
+}
+
+func (f *fakeAggregator) SubmitBlindedBlock(block *commonTypes.VersionedSignedBlindedBeaconBlock) error {
+	return f.submitErr
+}
+
+// newTestBuilderApi connects a builder API service to the fake aggregator, relaying messages
+// between the two clients the way the core does
+func newTestBuilderApi(t *testing.T, aggregator *fakeAggregator) *BuilderApiService {
+	registry := coreCommon.ModuleRegistry{}
+	if err := registry.RegisterName(aggregator.Name(), aggregator); err != nil {
+		t.Fatal(err)
+	}
+
+	knownCallbacks := map[string]bool{"core_ping": true}
+	var aggregatorCallbacks map[string]*coreCommon.Callback
+	for _, module := range registry.Modules() {
+		aggregatorCallbacks = module.Callbacks
+		for method := range module.Callbacks {
+			knownCallbacks[module.Name+"_"+method] = true
+		}
+	}
+
+	_, aggregatorClient, aggregatorChans, err := coreCommon.NewClient(context.Background(), aggregator.Name(), aggregatorCallbacks, knownCallbacks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, apiClient, apiChans, err := coreCommon.NewClient(context.Background(), "builderApi", nil, knownCallbacks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	incoming := map[string]chan coreCommon.JsonRPCMessage{
+		aggregator.Name(): aggregatorChans.Incoming,
+		"builderApi":      apiChans.Incoming,
+	}
+	route := func(origin string, msg coreCommon.JsonRPCMessage) {
+		target := msg.Origin
+		if !msg.IsResponse() {
+			target = msg.Namespace()
+			if msg.Origin == "" {
+				msg.Origin = origin
+			}
+		}
+		if ch, ok := incoming[target]; ok {
+			ch <- msg
+		}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case msg := <-aggregatorChans.Outgoing:
+				route(aggregator.Name(), msg)
+			case msg := <-apiChans.Outgoing:
+				route("builderApi", msg)
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		wg.Wait()
+		apiClient.Close()
+		aggregatorClient.Close()
+	})
+
+	b := NewBuilderApiService()
+	b.log.Logger.SetOutput(io.Discard)
+	if err := b.ConnectCore(apiClient, "ping"); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func testBid() spec.VersionedSignedBuilderBid {
+	return spec.VersionedSignedBuilderBid{
+		Version: consensusspec.DataVersionCapella,
+		Capella: &builderCapella.SignedBuilderBid{
+			Message: &builderCapella.BuilderBid{
+				Header: &capella.ExecutionPayloadHeader{},
+				Value:  uint256.NewInt(23),
+			},
+		},
+	}
+}
+
+func testBlindedBlock(t *testing.T) []byte {
+	return testBlindedBlockAtSlot(t, 0)
+}
+
+func testBlindedBlockAtSlot(t *testing.T, slot phase0.Slot) []byte {
+	block := &commonTypes.VersionedSignedBlindedBeaconBlock{
+		Capella: &apiv1Capella.SignedBlindedBeaconBlock{
+			Message: &apiv1Capella.BlindedBeaconBlock{
+				Slot: slot,
+				Body: &apiv1Capella.BlindedBeaconBlockBody{
+					ETH1Data:               &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+					ProposerSlashings:      []*phase0.ProposerSlashing{},
+					AttesterSlashings:      []*phase0.AttesterSlashing{},
+					Attestations:           []*phase0.Attestation{},
+					Deposits:               []*phase0.Deposit{},
+					VoluntaryExits:         []*phase0.SignedVoluntaryExit{},
+					BLSToExecutionChanges:  []*capella.SignedBLSToExecutionChange{},
+					SyncAggregate:          &altair.SyncAggregate{SyncCommitteeBits: make([]byte, 64)},
+					ExecutionPayloadHeader: &capella.ExecutionPayloadHeader{},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func doRequest(handler http.Handler, method, path string, body []byte, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetHeaderConformance(t *testing.T) {
+	path := "/eth/v1/builder/header/1/" + testParentHash + "/" + testPubkey
+
+	tests := []struct {
+		name       string
+		aggregator *fakeAggregator
+		path       string
+		wantStatus int
+		wantHeader string
+	}{
+		{name: "Bid", aggregator: &fakeAggregator{header: []spec.VersionedSignedBuilderBid{testBid()}}, path: path, wantStatus: http.StatusOK, wantHeader: "capella"},
+		{name: "NoBid", aggregator: &fakeAggregator{headerErr: &common.NoContentError{Message: "no bids"}}, path: path, wantStatus: http.StatusNoContent},
+		{name: "EmptyResult", aggregator: &fakeAggregator{}, path: path, wantStatus: http.StatusNoContent},
+		{name: "InvalidParams", aggregator: &fakeAggregator{headerErr: &common.InvalidParamsError{Message: "invalid proposerPubkey"}}, path: path, wantStatus: http.StatusBadRequest},
+		{name: "Timeout", aggregator: &fakeAggregator{headerErr: &common.InternalServerError{Code: common.RPCTimeoutErrorCode, Message: "timeout"}}, path: path, wantStatus: http.StatusGatewayTimeout},
+		{name: "InternalError", aggregator: &fakeAggregator{headerErr: &common.InternalServerError{Code: common.RPCInternalErrorCode, Message: "crashed"}}, path: path, wantStatus: http.StatusInternalServerError},
+		{name: "ShortPubkey", aggregator: &fakeAggregator{}, path: "/eth/v1/builder/header/1/" + testParentHash + "/0xabcd", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(newTestBuilderApi(t, tt.aggregator).getRouter(), http.MethodGet, tt.path, nil, nil)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if got := rr.Header().Get(HeaderEthConsensusVersion); got != tt.wantHeader {
+				t.Errorf("Expected %s header %q, got %q", HeaderEthConsensusVersion, tt.wantHeader, got)
+			}
+
+			switch rr.Code {
+			case http.StatusNoContent:
+				if rr.Body.Len() != 0 {
+					t.Errorf("Expected empty body, got %q", rr.Body.String())
+				}
+			case http.StatusOK:
+				var bid spec.VersionedSignedBuilderBid
+				if err := json.Unmarshal(rr.Body.Bytes(), &bid); err != nil {
+					t.Fatalf("Invalid bid response: %v", err)
+				}
+				if bid.Version != consensusspec.DataVersionCapella {
+					t.Errorf("Expected capella bid, got %s", bid.Version)
+				}
+			default:
+				var errResp httpErrorResp
+				if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("Invalid error response: %v", err)
+				}
+				if errResp.Code != tt.wantStatus || errResp.Message == "" {
+					t.Errorf("Unexpected error response %+v", errResp)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPayloadConformance(t *testing.T) {
+	path := "/eth/v1/builder/blinded_blocks"
+	block := testBlindedBlock(t)
+	payload := []commonTypes.VersionedExecutionPayloadV2WithVersionName{{VersionName: "capella"}}
+
+	tests := []struct {
+		name       string
+		aggregator *fakeAggregator
+		body       []byte
+		header     http.Header
+		wantStatus int
+		wantHeader string
+	}{
+		{name: "Payload", aggregator: &fakeAggregator{payload: payload}, body: block, wantStatus: http.StatusOK, wantHeader: "capella"},
+		{name: "MatchingVersionHeader", aggregator: &fakeAggregator{payload: payload}, body: block, header: http.Header{HeaderEthConsensusVersion: {"capella"}}, wantStatus: http.StatusOK, wantHeader: "capella"},
+		{name: "MismatchedVersionHeader", aggregator: &fakeAggregator{payload: payload}, body: block, header: http.Header{HeaderEthConsensusVersion: {"deneb"}}, wantStatus: http.StatusBadRequest},
+		{name: "MalformedBlock", aggregator: &fakeAggregator{payload: payload}, body: []byte(`{"message":{}}`), wantStatus: http.StatusBadRequest},
+		{name: "InvalidParams", aggregator: &fakeAggregator{payloadErr: &common.InvalidParamsError{Message: "invalid block"}}, body: block, wantStatus: http.StatusBadRequest},
+		{name: "NoPayload", aggregator: &fakeAggregator{}, body: block, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(newTestBuilderApi(t, tt.aggregator).getRouter(), http.MethodPost, path, tt.body, tt.header)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if got := rr.Header().Get(HeaderEthConsensusVersion); got != tt.wantHeader {
+				t.Errorf("Expected %s header %q, got %q", HeaderEthConsensusVersion, tt.wantHeader, got)
+			}
+			if rr.Code != http.StatusOK {
+				var errResp httpErrorResp
+				if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("Invalid error response: %v", err)
+				}
+				if errResp.Code != tt.wantStatus || errResp.Message == "" {
+					t.Errorf("Unexpected error response %+v", errResp)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmitBlindedBlockV2Conformance(t *testing.T) {
+	path := "/eth/v2/builder/blinded_blocks"
+	block := testBlindedBlock(t)
+
+	tests := []struct {
+		name       string
+		aggregator *fakeAggregator
+		body       []byte
+		header     http.Header
+		wantStatus int
+	}{
+		{name: "Accepted", aggregator: &fakeAggregator{}, body: block, wantStatus: http.StatusAccepted},
+		{name: "MatchingVersionHeader", aggregator: &fakeAggregator{}, body: block, header: http.Header{HeaderEthConsensusVersion: {"capella"}}, wantStatus: http.StatusAccepted},
+		{name: "MismatchedVersionHeader", aggregator: &fakeAggregator{}, body: block, header: http.Header{HeaderEthConsensusVersion: {"deneb"}}, wantStatus: http.StatusBadRequest},
+		{name: "MalformedBlock", aggregator: &fakeAggregator{}, body: []byte(`{"message":{}}`), wantStatus: http.StatusBadRequest},
+		{name: "InvalidParams", aggregator: &fakeAggregator{submitErr: &common.InvalidParamsError{Message: "invalid block"}}, body: block, wantStatus: http.StatusBadRequest},
+		{name: "NotDelivered", aggregator: &fakeAggregator{submitErr: errors.New("block not delivered")}, body: block, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(newTestBuilderApi(t, tt.aggregator).getRouter(), http.MethodPost, path, tt.body, tt.header)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code == http.StatusAccepted && rr.Body.Len() != 0 {
+				t.Errorf("Expected no body with the accepted response, got %q", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetPayloadForkSchedule(t *testing.T) {
+	path := "/eth/v1/builder/blinded_blocks"
+	payload := []commonTypes.VersionedExecutionPayloadV2WithVersionName{{VersionName: "capella"}}
+
+	// capella starts at epoch 256 and electra at epoch 115968 on holesky, electra at epoch 364032 on
+	// mainnet, electra has no builder types in this build
+	tests := []struct {
+		name       string
+		network    string
+		slot       phase0.Slot
+		wantStatus int
+	}{
+		{name: "CapellaSlot", network: network.Holesky, slot: 256 * 32, wantStatus: http.StatusOK},
+		{name: "BellatrixSlot", network: network.Holesky, slot: 255 * 32, wantStatus: http.StatusBadRequest},
+		{name: "ElectraSlot", network: network.Holesky, slot: 115968 * 32, wantStatus: http.StatusBadRequest},
+		{name: "MainnetElectraSlot", network: network.Mainnet, slot: 364032*32 + 1, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBuilderApi(t, &fakeAggregator{payload: payload})
+			b.network, _ = network.Preset(tt.network)
+
+			rr := doRequest(b.getRouter(), http.MethodPost, path, testBlindedBlockAtSlot(t, tt.slot), nil)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestElectraRequest(t *testing.T) {
+	b := newTestBuilderApi(t, &fakeAggregator{})
+	b.network, _ = network.Preset(network.Mainnet)
+
+	// electra is accepted by the API document but refused as a fork without builder types in this build
+	header := http.Header{HeaderEthConsensusVersion: {"electra"}}
+	rr := doRequest(b.getRouter(), http.MethodPost, pathGetPayload, testBlindedBlockAtSlot(t, 364032*32+1), header)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), network.ErrUnsupportedFork.Error()) {
+		t.Errorf("Expected the electra request to be refused as an unsupported fork, got %s", rr.Body.String())
+	}
+}
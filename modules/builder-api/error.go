@@ -6,9 +6,24 @@ import (
 
 var (
 	errServerAlreadyRunning = errors.New("server already running")
+
+	errNoListenAddresses       = errors.New("no listen addresses")
+	errMissingListenHost       = errors.New("missing listen host")
+	errMissingListenPort       = errors.New("missing listen port")
+	errMissingSocketPath       = errors.New("missing unix socket path")
+	errUnsupportedListenScheme = errors.New("unsupported listen scheme")
+	errMissingTLSFiles         = errors.New("https listen address requires tls-cert and tls-key")
+	errUnexpectedTLSFiles      = errors.New("tls-cert and tls-key are only supported for https listen addresses")
+	errMultipleListenerAuth    = errors.New("listen address can not set both basic auth and an auth token")
+	errUnauthorized            = errors.New("unauthorized")
 	ErrLength               = errors.New("invalid length")
 
-	errInvalidSlotNumber = errors.New("invalid slot number")
-	errInvalidPubkey	 = errors.New("invalid pubkey")
-	errInvalidHash		 = errors.New("invalid hash")
+	errInvalidSlotNumber  = errors.New("invalid slot number")
+	errMissingRequestBody = errors.New("missing request body")
+
+	errMissingRegistrationMessage   = errors.New("missing registration message")
+	errInvalidRegistrationSignature = errors.New("invalid registration signature")
+
+	errConsensusVersionMismatch = errors.New("consensus version header does not match the block version")
+	errForkVersionMismatch      = errors.New("block version does not match the fork of the slot")
 )
@@ -0,0 +1,41 @@
+package builderapi
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// pathMetrics serves the expvar metrics of every module on the metrics listeners
+const pathMetrics = "/debug/vars"
+
+// hiddenMetrics are the expvar variables not served, the command line holds private keys and listener credentials
+var hiddenMetrics = map[string]bool{"cmdline": true}
+
+// handleMetrics writes the expvar variables as a JSON object, like expvar.Handler without the hidden variables
+func handleMetrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fmt.Fprintf(w, "{\n")
+	first := true
+	expvar.Do(func(kv expvar.KeyValue) {
+		if hiddenMetrics[kv.Key] {
+			return
+		}
+		if !first {
+			fmt.Fprintf(w, ",\n")
+		}
+		first = false
+		fmt.Fprintf(w, "%q: %s", kv.Key, kv.Value)
+	})
+	fmt.Fprintf(w, "\n}\n")
+}
+
+// getMetricsRouter returns the handler of the metrics listeners, which are separate from the builder API
+// listeners so the metrics are only reachable where the operator chooses to serve them
+func (b *BuilderApiService) getMetricsRouter() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc(pathMetrics, handleMetrics).Methods(http.MethodGet)
+	return LoggingMiddleware(b.log, r)
+}
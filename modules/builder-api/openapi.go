@@ -0,0 +1,329 @@
+package builderapi
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pon-network/mev-plus/common/network"
+)
+
+// openapiTemplate is the OpenAPI document describing every route served by the builder API, without
+// the consensus versions which are taken from the fork table of the network package
+//
+//go:embed openapi.json
+var openapiTemplate []byte
+
+// openapiSpec is the OpenAPI document served and validated against
+var openapiSpec = mustBuildOpenAPISpec(openapiTemplate)
+
+// mustBuildOpenAPISpec sets the pattern of the ConsensusVersion schema to the forks of the network package,
+// which refuses the forks without builder types in this build with its own error
+func mustBuildOpenAPISpec(template []byte) []byte {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(template, &doc); err != nil {
+		panic(fmt.Errorf("invalid openapi document: %w", err))
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	version, ok := schemas["ConsensusVersion"].(map[string]interface{})
+	if !ok {
+		panic("openapi document has no ConsensusVersion schema")
+	}
+	version["pattern"] = "^(" + strings.Join(network.BuilderForkNames(), "|") + ")$"
+
+	spec, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		panic(fmt.Errorf("invalid openapi document: %w", err))
+	}
+	return spec
+}
+
+type openapiDocument struct {
+	Paths      map[string]map[string]*openapiOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]*openapiSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openapiOperation struct {
+	OperationID  string              `json:"operationId"`
+	Parameters   []*openapiParameter `json:"parameters"`
+	RequestBody  *openapiRequestBody `json:"requestBody"`
+	MaxBodyBytes int64               `json:"x-max-body-bytes"`
+}
+
+type openapiParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *openapiSchema `json:"schema"`
+}
+
+type openapiRequestBody struct {
+	Required bool `json:"required"`
+	Content  map[string]struct {
+		Schema *openapiSchema `json:"schema"`
+	} `json:"content"`
+}
+
+// openapiSchema is the subset of the OpenAPI schema object that requests are validated against
+type openapiSchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Pattern    string                    `json:"pattern"`
+	Required   []string                  `json:"required"`
+	Properties map[string]*openapiSchema `json:"properties"`
+	Items      *openapiSchema            `json:"items"`
+	MaxItems   *int                      `json:"maxItems"`
+
+	pattern *regexp.Regexp
+}
+
+// requestValidator validates incoming requests against the operations of the OpenAPI document
+type requestValidator struct {
+	operations map[string]map[string]*openapiOperation // path template -> method -> operation
+}
+
+func newRequestValidator(spec []byte) (*requestValidator, error) {
+	var doc openapiDocument
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+
+	v := &requestValidator{operations: make(map[string]map[string]*openapiOperation)}
+	for path, methods := range doc.Paths {
+		v.operations[path] = make(map[string]*openapiOperation)
+		for method, op := range methods {
+			for _, param := range op.Parameters {
+				schema, err := doc.resolve(param.Schema)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s parameter %s: %w", method, path, param.Name, err)
+				}
+				param.Schema = schema
+			}
+			if op.RequestBody != nil {
+				content, ok := op.RequestBody.Content["application/json"]
+				if !ok {
+					return nil, fmt.Errorf("%s %s: request body is not application/json", method, path)
+				}
+				schema, err := doc.resolve(content.Schema)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s request body: %w", method, path, err)
+				}
+				content.Schema = schema
+				op.RequestBody.Content["application/json"] = content
+			}
+			v.operations[path][strings.ToUpper(method)] = op
+		}
+	}
+
+	return v, nil
+}
+
+// resolve replaces references to component schemas and compiles patterns
+func (doc *openapiDocument) resolve(schema *openapiSchema) (*openapiSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		ref, ok := doc.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema reference %s", schema.Ref)
+		}
+		return doc.resolve(ref)
+	}
+
+	if schema.Pattern != "" && schema.pattern == nil {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", schema.Pattern, err)
+		}
+		schema.pattern = pattern
+	}
+
+	for name, property := range schema.Properties {
+		resolved, err := doc.resolve(property)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		schema.Properties[name] = resolved
+	}
+
+	items, err := doc.resolve(schema.Items)
+	if err != nil {
+		return nil, fmt.Errorf("items: %w", err)
+	}
+	schema.Items = items
+
+	return schema, nil
+}
+
+func (v *requestValidator) operation(pathTemplate, method string) *openapiOperation {
+	methods, ok := v.operations[pathTemplate]
+	if !ok {
+		return nil
+	}
+	return methods[method]
+}
+
+// validateRequest checks the path parameters, headers and body of a request against the operation
+// documented for its route before passing it on to the handler
+func (b *BuilderApiService) validateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route := mux.CurrentRoute(req)
+		if route == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		pathTemplate, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		op := b.requestValidator.operation(pathTemplate, req.Method)
+		if op == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		vars := mux.Vars(req)
+		for _, param := range op.Parameters {
+			var value string
+			switch param.In {
+			case "path":
+				value = vars[param.Name]
+			case "header":
+				value = req.Header.Get(param.Name)
+			case "query":
+				value = req.URL.Query().Get(param.Name)
+			}
+			if value == "" {
+				if param.Required {
+					b.respondError(w, http.StatusBadRequest, fmt.Sprintf("missing %s parameter %s", param.In, param.Name))
+					return
+				}
+				continue
+			}
+			if err := validateValue(param.Schema, value); err != nil {
+				b.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s parameter %s: %v", param.In, param.Name, err))
+				return
+			}
+		}
+
+		if op.RequestBody != nil {
+			body := io.Reader(req.Body)
+			if op.MaxBodyBytes > 0 {
+				body = http.MaxBytesReader(w, req.Body, op.MaxBodyBytes)
+			}
+			raw, err := io.ReadAll(body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					b.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", op.MaxBodyBytes))
+					return
+				}
+				b.respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if len(raw) == 0 {
+				if op.RequestBody.Required {
+					b.respondError(w, http.StatusBadRequest, errMissingRequestBody.Error())
+					return
+				}
+			} else {
+				decoder := json.NewDecoder(bytes.NewReader(raw))
+				decoder.UseNumber()
+				var value any
+				if err := decoder.Decode(&value); err != nil {
+					b.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+					return
+				}
+				if err := validateValue(op.RequestBody.Content["application/json"].Schema, value); err != nil {
+					b.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+					return
+				}
+			}
+
+			req.Body = io.NopCloser(bytes.NewReader(raw))
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// validateValue checks a decoded JSON value, or a raw parameter string, against a schema
+func validateValue(schema *openapiSchema, value any) error {
+	if schema == nil {
+		return nil
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return errors.New("expected an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("missing field %s", name)
+			}
+		}
+		for name, property := range schema.Properties {
+			field, ok := object[name]
+			if !ok {
+				continue
+			}
+			if err := validateValue(property, field); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return errors.New("expected an array")
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			return fmt.Errorf("more than %d items", *schema.MaxItems)
+		}
+		for i, item := range array {
+			if err := validateValue(schema.Items, item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return errors.New("expected a string")
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(str) {
+			return fmt.Errorf("%q does not match %s", str, schema.Pattern)
+		}
+	case "integer":
+		if number, ok := value.(json.Number); !ok {
+			return errors.New("expected an integer")
+		} else if _, err := number.Int64(); err != nil {
+			return errors.New("expected an integer")
+		}
+	}
+
+	return nil
+}
+
+func (b *BuilderApiService) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	// Get call.
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapiSpec)
+}
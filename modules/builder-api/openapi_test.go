@@ -0,0 +1,108 @@
+package builderapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pon-network/mev-plus/common/network"
+)
+
+func TestOpenAPIDocumentsRoutes(t *testing.T) {
+	validator, err := newRequestValidator(openapiSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := map[string]string{
+		pathRoot:                 http.MethodGet,
+		pathOpenAPI:              http.MethodGet,
+		pathStatus:               http.MethodGet,
+		pathRegisterValidator:    http.MethodPost,
+		pathGetHeader:            http.MethodGet,
+		pathGetPayload:           http.MethodPost,
+		pathSubmitBlindedBlockV2: http.MethodPost,
+	}
+	for path, method := range routes {
+		if validator.operation(path, method) == nil {
+			t.Errorf("%s %s is not documented in the OpenAPI document", method, path)
+		}
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	rr := doRequest(newTestBuilderApi(t, &fakeAggregator{}).getRouter(), http.MethodGet, pathOpenAPI, nil, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	var doc openapiDocument
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid OpenAPI document: %v", err)
+	}
+	if _, ok := doc.Paths[pathGetHeader]; !ok {
+		t.Errorf("OpenAPI document does not describe %s", pathGetHeader)
+	}
+
+	// the consensus versions follow the fork table of the network package
+	want := "^(" + strings.Join(network.BuilderForkNames(), "|") + ")$"
+	if version := doc.Components.Schemas["ConsensusVersion"]; version == nil || version.Pattern != want {
+		t.Errorf("Expected the ConsensusVersion pattern %s, got %+v", want, version)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	handler := newTestBuilderApi(t, &fakeAggregator{}).getRouter()
+
+	registration := `{"message":{"fee_recipient":"0xabcf8e0d4e9587369b2301d0790347320302cc09","gas_limit":"30000000","timestamp":"1700000000","pubkey":"` + testPubkey + `"},"signature":"0x` + strings.Repeat("00", 96) + `"}`
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		header     http.Header
+		wantStatus int
+	}{
+		{name: "NonNumericSlot", method: http.MethodGet, path: "/eth/v1/builder/header/abc/" + testParentHash + "/" + testPubkey, wantStatus: http.StatusBadRequest},
+		{name: "ShortParentHash", method: http.MethodGet, path: "/eth/v1/builder/header/1/0x1234/" + testPubkey, wantStatus: http.StatusBadRequest},
+		{name: "NonHexPubkey", method: http.MethodGet, path: "/eth/v1/builder/header/1/" + testParentHash + "/0x" + strings.Repeat("zz", 48), wantStatus: http.StatusBadRequest},
+		{name: "UnknownConsensusVersion", method: http.MethodPost, path: pathGetPayload, body: "{}", header: http.Header{HeaderEthConsensusVersion: {"phase0"}}, wantStatus: http.StatusBadRequest},
+		{name: "MissingBody", method: http.MethodPost, path: pathRegisterValidator, wantStatus: http.StatusBadRequest},
+		{name: "RegistrationsNotArray", method: http.MethodPost, path: pathRegisterValidator, body: registration, wantStatus: http.StatusBadRequest},
+		{name: "InvalidFeeRecipient", method: http.MethodPost, path: pathRegisterValidator, body: "[" + strings.Replace(registration, "0xabcf", "0xzzzz", 1) + "]", wantStatus: http.StatusBadRequest},
+		{name: "MissingSignature", method: http.MethodPost, path: pathRegisterValidator, body: `[{"message":{}}]`, wantStatus: http.StatusBadRequest},
+		{name: "BlindedBlockMissingMessage", method: http.MethodPost, path: pathGetPayload, body: `{"signature":"0x00"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(handler, tt.method, tt.path, []byte(tt.body), tt.header)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			var errResp httpErrorResp
+			if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil || errResp.Message == "" {
+				t.Errorf("Expected a structured error message, got %q", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestBodySizeLimit(t *testing.T) {
+	spec := `{"paths":{"/eth/v1/builder/blinded_blocks":{"post":{"x-max-body-bytes":16,"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}}}}}}`
+	validator, err := newRequestValidator([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewBuilderApiService()
+	b.log.Logger.SetOutput(io.Discard)
+	b.requestValidator = validator
+
+	rr := doRequest(b.getRouter(), http.MethodPost, pathGetPayload, []byte(`{"message":"this body is too long"}`), nil)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status 413, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
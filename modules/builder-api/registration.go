@@ -0,0 +1,120 @@
+package builderapi
+
+import (
+	"runtime"
+	"sync"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pon-network/mev-plus/modules/relay/signing"
+)
+
+// registrationFailure describes a validator registration that was rejected
+type registrationFailure struct {
+	Pubkey string `json:"pubkey"`
+	Reason string `json:"reason"`
+}
+
+type registrationErrorResp struct {
+	Code     int                   `json:"code"`
+	Message  string                `json:"message"`
+	Failures []registrationFailure `json:"failures"`
+}
+
+// verifiedRegistration is the last registration of a validator that passed signature verification
+type verifiedRegistration struct {
+	root      [32]byte
+	signature phase0.BLSSignature
+}
+
+// registrationVerifier checks validator registration signatures against the application builder domain,
+// remembering the last verified registration per validator so repeated submissions are not re-verified
+type registrationVerifier struct {
+	domain phase0.Domain
+
+	mu       sync.Mutex
+	verified map[phase0.BLSPubKey]verifiedRegistration
+}
+
+func newRegistrationVerifier(genesisForkVersion string) (*registrationVerifier, error) {
+	// The application builder domain is computed with the genesis fork version and a zero genesis validators root
+	domain, err := signing.ComputeDomain(signing.DomainTypeAppBuilder, genesisForkVersion, phase0.Root{}.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &registrationVerifier{
+		domain:   phase0.Domain(domain),
+		verified: make(map[phase0.BLSPubKey]verifiedRegistration),
+	}, nil
+}
+
+// verify checks every registration and splits them into the valid ones and the failures
+func (v *registrationVerifier) verify(payload []apiv1.SignedValidatorRegistration) (valid []apiv1.SignedValidatorRegistration, failures []registrationFailure) {
+
+	results := make([]string, len(payload))
+
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i] = v.verifyOne(&payload[i])
+			}
+		}()
+	}
+	for i := range payload {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for i, reason := range results {
+		if reason != "" {
+			pubkey := ""
+			if payload[i].Message != nil {
+				pubkey = payload[i].Message.Pubkey.String()
+			}
+			failures = append(failures, registrationFailure{Pubkey: pubkey, Reason: reason})
+			continue
+		}
+		valid = append(valid, payload[i])
+	}
+
+	return valid, failures
+}
+
+// verifyOne returns the reason a registration is invalid, or an empty string if it is valid
+func (v *registrationVerifier) verifyOne(registration *apiv1.SignedValidatorRegistration) string {
+	if registration.Message == nil {
+		return errMissingRegistrationMessage.Error()
+	}
+
+	root, err := registration.Message.HashTreeRoot()
+	if err != nil {
+		return err.Error()
+	}
+
+	v.mu.Lock()
+	previous, ok := v.verified[registration.Message.Pubkey]
+	v.mu.Unlock()
+	if ok && previous.root == root && previous.signature == registration.Signature {
+		return ""
+	}
+
+	ok, err = signing.VerifySignedRoot(root, v.domain, registration.Signature[:], registration.Message.Pubkey[:])
+	if err != nil {
+		return err.Error()
+	}
+	if !ok {
+		return errInvalidRegistrationSignature.Error()
+	}
+
+	v.mu.Lock()
+	v.verified[registration.Message.Pubkey] = verifiedRegistration{root: root, signature: registration.Signature}
+	v.mu.Unlock()
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package builderapi
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pon-network/mev-plus/modules/relay/signing"
+)
+
+// testSigner signs messages with a locally generated BLS secret key
+type testSigner struct {
+	sk     *signing.SecretKey
+	pubkey phase0.BLSPubKey
+}
+
+func newTestSigner(t *testing.T, seed byte) testSigner {
+	skBytes := make([]byte, signing.SecretKeyLength)
+	skBytes[31] = seed
+	sk, err := signing.SecretKeyFromBytes(skBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := testSigner{sk: sk}
+	pkBytes := signing.PublicKeyFromSecretKey(sk).Bytes()
+	copy(s.pubkey[:], pkBytes[:])
+	return s
+}
+
+func (s testSigner) sign(t *testing.T, root [32]byte, domain phase0.Domain) phase0.BLSSignature {
+	sig, err := signing.SignRoot(root, domain, s.sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return phase0.BLSSignature(sig.Bytes())
+}
+
+func (s testSigner) registration(t *testing.T, domain phase0.Domain) apiv1.SignedValidatorRegistration {
+	msg := &apiv1.ValidatorRegistration{
+		FeeRecipient: bellatrix.ExecutionAddress{0x01},
+		GasLimit:     30000000,
+		Timestamp:    time.Unix(1700000000, 0),
+		Pubkey:       s.pubkey,
+	}
+	root, err := msg.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return apiv1.SignedValidatorRegistration{Message: msg, Signature: s.sign(t, root, domain)}
+}
+
+func TestRegistrationVerifier(t *testing.T) {
+	verifier, err := newRegistrationVerifier("0x00000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ValidRegistrations", func(t *testing.T) {
+		payload := []apiv1.SignedValidatorRegistration{
+			newTestSigner(t, 11).registration(t, verifier.domain),
+			newTestSigner(t, 12).registration(t, verifier.domain),
+		}
+		valid, failures := verifier.verify(payload)
+		if len(failures) != 0 {
+			t.Fatalf("Expected no failures, got %v", failures)
+		}
+		if len(valid) != 2 {
+			t.Fatalf("Expected 2 valid registrations, got %d", len(valid))
+		}
+	})
+
+	t.Run("InvalidRegistrationsRejectedPerItem", func(t *testing.T) {
+		wrongDomain, err := signing.ComputeDomain(signing.DomainTypeAppBuilder, "0x01017000", phase0.Root{}.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		good := newTestSigner(t, 21).registration(t, verifier.domain)
+		badDomain := newTestSigner(t, 22).registration(t, phase0.Domain(wrongDomain))
+		tampered := newTestSigner(t, 23).registration(t, verifier.domain)
+		tampered.Message.GasLimit++
+
+		valid, failures := verifier.verify([]apiv1.SignedValidatorRegistration{good, badDomain, tampered})
+		if len(valid) != 1 || valid[0].Message.Pubkey != good.Message.Pubkey {
+			t.Fatalf("Expected only the correctly signed registration to be valid, got %d", len(valid))
+		}
+		if len(failures) != 2 {
+			t.Fatalf("Expected 2 failures, got %d", len(failures))
+		}
+		if failures[0].Pubkey != badDomain.Message.Pubkey.String() || failures[1].Pubkey != tampered.Message.Pubkey.String() {
+			t.Errorf("Failures do not list the rejected pubkeys: %v", failures)
+		}
+	})
+
+	t.Run("MissingMessage", func(t *testing.T) {
+		_, failures := verifier.verify([]apiv1.SignedValidatorRegistration{{}})
+		if len(failures) != 1 || failures[0].Reason != errMissingRegistrationMessage.Error() {
+			t.Errorf("Expected missing message failure, got %v", failures)
+		}
+	})
+}
@@ -1,13 +1,18 @@
 package builderapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
 	coreCommon "github.com/pon-network/mev-plus/core/common"
 	"github.com/pon-network/mev-plus/modules/builder-api/config"
 	"github.com/sirupsen/logrus"
@@ -16,9 +21,18 @@ import (
 
 type BuilderApiService struct {
 	log        *logrus.Entry
-	srv        *http.Server
+	servers    []*http.Server
 	coreClient *coreCommon.Client
 
+	registrationVerifier *registrationVerifier
+	requestValidator     *requestValidator
+	// network is the fork schedule blinded blocks are checked against, nil if no network is set
+	network *network.Network
+
+	// slot critical responses that have been received from the aggregator and are being written
+	pendingResponses inflightTracker
+	drained          bool
+
 	cfg config.BuilderApiConfig
 }
 
@@ -28,6 +42,19 @@ func NewBuilderApiService() *BuilderApiService {
 		log: logrus.NewEntry(logrus.New()),
 		cfg: config.BuilderApiConfigDefaults,
 	}
+
+	verifier, err := newRegistrationVerifier(b.cfg.GenesisForkVersion)
+	if err != nil {
+		panic(err)
+	}
+	b.registrationVerifier = verifier
+
+	validator, err := newRequestValidator(openapiSpec)
+	if err != nil {
+		panic(err)
+	}
+	b.requestValidator = validator
+
 	return b
 }
 
@@ -37,8 +64,20 @@ func (b *BuilderApiService) CliCommand() *cli.Command {
 
 func (b *BuilderApiService) Configure(moduleFlags common.ModuleFlags) (err error) {
 
+	// the network sets the default genesis fork version that the fork version flag overrides, and the fork schedule blinded blocks are checked against
+	if flagValue, ok := moduleFlags[config.NetworkFlag.Name]; ok {
+		n, err := network.Load(flagValue)
+		if err != nil {
+			return fmt.Errorf("-%s: %w", config.NetworkFlag.Name, err)
+		}
+		b.cfg.GenesisForkVersion = n.GenesisForkVersion
+		b.network = n
+	}
+
 	for flagName, flagValue := range moduleFlags {
 		switch flagName {
+		case config.NetworkFlag.Name:
+			// applied before the other flags
 		case config.LoggerLevelFlag.Name:
 			logLevel, err := logrus.ParseLevel(flagValue)
 			if err != nil {
@@ -55,9 +94,18 @@ func (b *BuilderApiService) Configure(moduleFlags common.ModuleFlags) (err error
 				return fmt.Errorf("invalid logger format %s", flagValue)
 			}
 		case config.ListenAddressFlag.Name:
-			b.cfg.ListenAddress, err = createUrl(flagValue)
+			b.cfg.ListenAddresses, err = parseListenAddresses(flagValue)
+			if err != nil {
+				return fmt.Errorf("-%s: %w", config.ListenAddressFlag.Name, err)
+			}
+		case config.MetricsListenAddressFlag.Name:
+			if flagValue == "" {
+				b.cfg.MetricsListenAddresses = nil
+				continue
+			}
+			b.cfg.MetricsListenAddresses, err = parseListenAddresses(flagValue)
 			if err != nil {
-				return fmt.Errorf("-%s: invalid url %q", config.ListenAddressFlag.Name, flagValue)
+				return fmt.Errorf("-%s: %w", config.MetricsListenAddressFlag.Name, err)
 			}
 		case config.ServerReadHeaderTimeoutMsFlag.Name:
 			flagValint, err := strconv.Atoi(flagValue)
@@ -89,11 +137,30 @@ func (b *BuilderApiService) Configure(moduleFlags common.ModuleFlags) (err error
 				return err
 			}
 			b.cfg.ServerMaxHeaderBytes = flagValint
+		case config.ShutdownTimeoutMsFlag.Name:
+			flagValint, err := strconv.Atoi(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.ShutdownTimeoutMs = flagValint
+		case config.GenesisForkVersionFlag.Name:
+			b.cfg.GenesisForkVersion = flagValue
+		case config.SkipRegistrationSignatureCheckFlag.Name:
+			skip, err := strconv.ParseBool(flagValue)
+			if err != nil {
+				return err
+			}
+			b.cfg.SkipRegistrationCheck = skip
 		default:
 			return fmt.Errorf("invalid flag %s", flagName)
 		}
 	}
 
+	b.registrationVerifier, err = newRegistrationVerifier(b.cfg.GenesisForkVersion)
+	if err != nil {
+		return fmt.Errorf("-%s: %w", config.GenesisForkVersionFlag.Name, err)
+	}
+
 	return nil
 }
 
@@ -118,25 +185,58 @@ func (b *BuilderApiService) ConnectCore(coreClient *coreCommon.Client, pingId st
 func (b *BuilderApiService) getRouter() http.Handler {
 	r := mux.NewRouter()
 	r.HandleFunc(pathRoot, b.handleRoot).Methods(http.MethodGet)
+	r.HandleFunc(pathOpenAPI, b.handleOpenAPI).Methods(http.MethodGet)
 	r.HandleFunc(pathStatus, b.handleStatus).Methods(http.MethodGet)
 	r.HandleFunc(pathRegisterValidator, b.handleRegisterValidator).Methods(http.MethodPost)
 	r.HandleFunc(pathGetHeader, b.handleGetHeader).Methods(http.MethodGet)
 	r.HandleFunc(pathGetPayload, b.handleGetPayload).Methods(http.MethodPost)
+	r.HandleFunc(pathSubmitBlindedBlockV2, b.handleSubmitBlindedBlockV2).Methods(http.MethodPost)
 
 	r.Use(mux.CORSMethodMiddleware(r))
+	r.Use(b.validateRequest)
 	loggedRouter := LoggingMiddleware(b.log, r)
 	return loggedRouter
 }
 
 func (b *BuilderApiService) Start() (err error) {
 
-	if b.srv != nil {
+	if len(b.servers) > 0 {
 		return errServerAlreadyRunning
 	}
 
-	b.srv = &http.Server{
-		Addr:    b.cfg.ListenAddress.Host,
-		Handler: b.getRouter(),
+	router := b.getRouter()
+	for _, address := range b.cfg.ListenAddresses {
+		if err := b.startServer(address, router, "Builder API"); err != nil {
+			b.closeServers()
+			return err
+		}
+	}
+
+	// The metrics are only served on their own listeners, off unless configured
+	metricsRouter := b.getMetricsRouter()
+	for _, address := range b.cfg.MetricsListenAddresses {
+		if err := b.startServer(address, metricsRouter, "metrics"); err != nil {
+			b.closeServers()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startServer serves the handler on the listen address, with the listener TLS and auth settings
+func (b *BuilderApiService) startServer(address *url.URL, handler http.Handler, name string) error {
+	l, err := newListener(address)
+	if err != nil {
+		return err
+	}
+	ln, err := l.listen()
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", l.url.String(), err)
+	}
+
+	srv := &http.Server{
+		Handler: l.authenticate(b, handler),
 
 		ReadTimeout:       time.Duration(b.cfg.ServerReadTimeoutMs) * time.Millisecond,
 		ReadHeaderTimeout: time.Duration(b.cfg.ServerReadHeaderTimeoutMs) * time.Millisecond,
@@ -145,35 +245,107 @@ func (b *BuilderApiService) Start() (err error) {
 
 		MaxHeaderBytes: b.cfg.ServerMaxHeaderBytes,
 	}
+	b.servers = append(b.servers, srv)
 
-	go func ()  {
-		listenErr := b.srv.ListenAndServe()
-		if listenErr != nil {
-			b.log.WithError(listenErr).Error("Failed to start Builder API server")
-			err = listenErr
+	go func() {
+		serveErr := l.serve(srv, ln)
+		if serveErr != nil && serveErr != http.ErrServerClosed {
+			b.log.WithError(serveErr).WithField("listenAddr", l.url.String()).Errorf("%s server failed", name)
 		}
-	} ()
+	}()
 
-	b.log.WithField("listenAddr", b.cfg.ListenAddress.String()).Info("Started Builder API server")
+	b.log.WithField("listenAddr", l.url.String()).Infof("Started %s server", name)
 
 	return nil
 }
 
+// ListenAddress returns the first address the builder API listens on
 func (b *BuilderApiService) ListenAddress() string {
-	return b.cfg.ListenAddress.String()
+	addresses := b.ListenAddresses()
+	if len(addresses) == 0 {
+		return ""
+	}
+	return addresses[0]
+}
+
+// ListenAddresses returns every address the builder API listens on, without credentials
+func (b *BuilderApiService) ListenAddresses() []string {
+	addresses := make([]string, 0, len(b.cfg.ListenAddresses))
+	for _, address := range b.cfg.ListenAddresses {
+		u := *address
+		u.User = nil
+		u.RawQuery = ""
+		addresses = append(addresses, u.String())
+	}
+	return addresses
+}
+
+// Drain stops the server accepting new requests and waits for in-flight requests to finish,
+// up to the shutdown timeout. Slot critical responses already being written are always let through.
+func (b *BuilderApiService) Drain(ctx context.Context) error {
+	if len(b.servers) == 0 || b.drained {
+		return nil
+	}
+	b.drained = true
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, time.Duration(b.cfg.ShutdownTimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	b.log.Info("Draining Builder API server")
+
+	var wg sync.WaitGroup
+	shutdownErrs := make([]error, len(b.servers))
+	for i, srv := range b.servers {
+		wg.Add(1)
+		go func(i int, srv *http.Server) {
+			defer wg.Done()
+			shutdownErrs[i] = srv.Shutdown(shutdownCtx)
+		}(i, srv)
+	}
+	wg.Wait()
+
+	err := errors.Join(shutdownErrs...)
+	if err == nil {
+		return nil
+	}
+
+	b.log.WithError(err).WithField("pendingResponses", b.pendingResponses.count()).Warn("Builder API server did not drain before the shutdown timeout")
+
+	// Defer shutdown until the slot critical responses are written, bounded by the server write timeout if set
+	writeCtx := context.Background()
+	if b.cfg.ServerWriteTimeoutMs > 0 {
+		var writeCancel context.CancelFunc
+		writeCtx, writeCancel = context.WithTimeout(writeCtx, time.Duration(b.cfg.ServerWriteTimeoutMs)*time.Millisecond)
+		defer writeCancel()
+	}
+	if waitErr := b.pendingResponses.wait(writeCtx); waitErr != nil {
+		b.log.WithError(waitErr).Error("Slot critical responses were not written before shutdown")
+	}
+
+	return err
 }
 
 func (b *BuilderApiService) Stop() error {
-	if b.srv == nil {
+	if len(b.servers) == 0 {
 		return nil
 	}
 
-	err := b.srv.Close()
-	if err != nil {
-		return err
+	// The core drains modules before stopping them, this is a no-op if the server was already drained
+	if err := b.Drain(context.Background()); err != nil {
+		b.log.WithError(err).Warn("Failed to drain Builder API server")
+	}
+
+	return b.closeServers()
+}
+
+func (b *BuilderApiService) closeServers() error {
+	var closeErrs []error
+	for _, srv := range b.servers {
+		closeErrs = append(closeErrs, srv.Close())
 	}
 
-	b.srv = nil
+	b.servers = nil
+	b.drained = false
 
-	return nil
+	return errors.Join(closeErrs...)
 }
@@ -0,0 +1,168 @@
+package builderapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+)
+
+func freeListenAddress(t *testing.T) *url.URL {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return &url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+func TestDrainWaitsForInflightPayload(t *testing.T) {
+	aggregator := &fakeAggregator{
+		payload: []commonTypes.VersionedExecutionPayloadV2WithVersionName{{VersionName: "capella"}},
+		release: make(chan struct{}),
+	}
+	b := newTestBuilderApi(t, aggregator)
+	address := freeListenAddress(t)
+	b.cfg.ListenAddresses = []*url.URL{address}
+	b.cfg.ShutdownTimeoutMs = 5000
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the server to accept connections
+	endpoint := address.String() + pathGetPayload
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", address.Host)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if i == 50 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	statusCode := make(chan int, 1)
+	go func() {
+		resp, err := http.Post(endpoint, "application/json", bytes.NewReader(testBlindedBlock(t)))
+		if err != nil {
+			statusCode <- 0
+			return
+		}
+		resp.Body.Close()
+		statusCode <- resp.StatusCode
+	}()
+
+	// Let the payload request reach the aggregator before draining, then release it mid-drain
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		close(aggregator.release)
+	}()
+
+	if err := b.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if code := <-statusCode; code != http.StatusOK {
+		t.Fatalf("Expected in-flight payload request to complete with 200, got %d", code)
+	}
+
+	if _, err := http.Get(address.String() + pathRoot); err == nil {
+		t.Error("Expected new requests to be refused after draining")
+	}
+
+	if err := b.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	aggregator := &fakeAggregator{release: make(chan struct{})}
+	defer close(aggregator.release)
+
+	b := newTestBuilderApi(t, aggregator)
+	address := freeListenAddress(t)
+	b.cfg.ListenAddresses = []*url.URL{address}
+	b.cfg.ShutdownTimeoutMs = 100
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	go http.Post(address.String()+pathGetPayload, "application/json", bytes.NewReader(testBlindedBlock(t)))
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	if err := b.Drain(context.Background()); err == nil {
+		t.Fatal("Expected drain to time out with a stuck request")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Drain took %s, longer than the shutdown timeout", elapsed)
+	}
+	if err := b.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitForListener(t *testing.T, address *url.URL) {
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", address.Host)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		if i == 50 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMetricsListener(t *testing.T) {
+	b := newTestBuilderApi(t, &fakeAggregator{})
+	address := freeListenAddress(t)
+	metricsAddress := freeListenAddress(t)
+	b.cfg.ListenAddresses = []*url.URL{address}
+	b.cfg.MetricsListenAddresses = []*url.URL{metricsAddress}
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+	waitForListener(t, address)
+	waitForListener(t, metricsAddress)
+
+	resp, err := http.Get(metricsAddress.String() + pathMetrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected metrics to be served with 200, got %d", resp.StatusCode)
+	}
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := vars["memstats"]; !ok {
+		t.Error("Expected the memstats expvar to be served")
+	}
+	if _, ok := vars["cmdline"]; ok {
+		t.Error("Expected the cmdline expvar to be hidden")
+	}
+
+	apiResp, err := http.Get(address.String() + pathMetrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiResp.Body.Close()
+	if apiResp.StatusCode == http.StatusOK {
+		t.Error("Expected the builder API listener not to serve the metrics")
+	}
+}
@@ -1,29 +1,94 @@
 package builderapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pon-network/mev-plus/common"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	HeaderKeySlotUID = "X-MEVPlusID"
-	HeaderKeyVersion = "X-MEVPlus-Version"
+	HeaderKeySlotUID          = "X-MEVPlusID"
+	HeaderKeyVersion          = "X-MEVPlus-Version"
+	HeaderEthConsensusVersion = "Eth-Consensus-Version"
 )
 
+// rpcErrorStatusCodes maps the JSON-RPC error codes returned over the core to the
+// HTTP status codes defined by the builder-specs, anything else is a 500
+var rpcErrorStatusCodes = map[int]int{
+	common.RPCNoContentErrorCode:                http.StatusNoContent,
+	common.RPCTimeoutErrorCode:                  http.StatusGatewayTimeout,
+	(&common.InvalidParamsError{}).ErrorCode():  http.StatusBadRequest,
+	(&common.InvalidRequestError{}).ErrorCode(): http.StatusBadRequest,
+	(&common.ParseError{}).ErrorCode():          http.StatusBadRequest,
+}
+
+// inflightTracker counts requests in progress so that shutdown can wait for them to finish
+type inflightTracker struct {
+	mu      sync.Mutex
+	pending int
+	idle    chan struct{} // closed when pending drops to zero
+}
+
+func (t *inflightTracker) add() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.pending++
+}
+
+func (t *inflightTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending--
+	if t.pending == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *inflightTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pending
+}
+
+// wait blocks until there are no requests in progress or the context is done
+func (t *inflightTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.pending == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func createUrl(urlString string) (*url.URL, error) {
 	if urlString == "" {
 		return nil, nil
 	}
-	if !strings.HasPrefix(urlString, "http") {
+	if !strings.Contains(urlString, "://") {
 		urlString = "http://" + urlString
 	}
 
@@ -48,6 +113,43 @@ func HexToPubkey(s string) (ret phase0.BLSPubKey, err error) {
 	return
 }
 
+// statusCodeForError returns the HTTP status code for an error returned by a call over the core
+func statusCodeForError(err error) int {
+	var rpcErr common.Error
+	if errors.As(err, &rpcErr) {
+		if code, ok := rpcErrorStatusCodes[rpcErr.ErrorCode()]; ok {
+			return code
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// respondCallError responds to a failed call over the core with the status code mapped from its error
+func (b *BuilderApiService) respondCallError(w http.ResponseWriter, err error) {
+	code := statusCodeForError(err)
+	if code == http.StatusNoContent {
+		b.respondNoContent(w)
+		return
+	}
+	b.respondError(w, code, err.Error())
+}
+
+func (b *BuilderApiService) respondNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *BuilderApiService) respondAccepted(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// respondOKWithVersion responds with the fork version of the response set in the Eth-Consensus-Version header
+func (b *BuilderApiService) respondOKWithVersion(w http.ResponseWriter, version string, response any) {
+	if version != "" {
+		w.Header().Set(HeaderEthConsensusVersion, version)
+	}
+	b.respondOK(w, response)
+}
+
 func (b *BuilderApiService) respondError(w http.ResponseWriter, code int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -58,6 +160,20 @@ func (b *BuilderApiService) respondError(w http.ResponseWriter, code int, messag
 	}
 }
 
+func (b *BuilderApiService) respondRegistrationFailures(w http.ResponseWriter, accepted int, failures []registrationFailure) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	resp := registrationErrorResp{
+		Code:     http.StatusBadRequest,
+		Message:  fmt.Sprintf("%d validator registrations rejected, %d accepted", len(failures), accepted),
+		Failures: failures,
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		b.log.WithField("response", resp).WithError(err).Error("Couldn't write error response")
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
 func (b *BuilderApiService) respondOK(w http.ResponseWriter, response any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -103,3 +219,28 @@ func LoggingMiddleware(logger *logrus.Entry, next http.Handler) http.Handler {
 		fmt.Println("*******************************")
 	})
 }
+
+// checkForkVersion checks the blinded block is of the fork scheduled for its slot, when the network is known.
+// Blocks for slots of forks without builder types in this build are refused.
+func (b *BuilderApiService) checkForkVersion(block *commonTypes.VersionedSignedBlindedBeaconBlock, blockVersion string) error {
+	if b.network == nil {
+		return nil
+	}
+
+	baseBlock, err := block.ToBaseSignedBlindedBeaconBlock()
+	if err != nil {
+		return err
+	}
+	if baseBlock.Message == nil {
+		return errMissingRequestBody
+	}
+
+	expected, err := b.network.DataVersionAtSlot(uint64(baseBlock.Message.Slot))
+	if err != nil {
+		return err
+	}
+	if expected.String() != blockVersion {
+		return fmt.Errorf("%w: %s block for a %s slot", errForkVersionMismatch, blockVersion, expected.String())
+	}
+	return nil
+}
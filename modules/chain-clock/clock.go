@@ -0,0 +1,125 @@
+package chainclock
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pon-network/mev-plus/common/network"
+	clockCommon "github.com/pon-network/mev-plus/modules/chain-clock/common"
+	"github.com/sirupsen/logrus"
+)
+
+var errBeforeGenesis = errors.New("the network has not reached genesis")
+
+// slotAt returns the slot at the time and the time elapsed since the slot started, it is false before genesis
+func (c *ChainClockService) slotAt(t time.Time) (uint64, time.Duration, bool) {
+	genesis := time.Unix(int64(c.network.GenesisTime), 0)
+	if t.Before(genesis) {
+		return 0, 0, false
+	}
+	elapsed := t.Sub(genesis)
+	slot := uint64(elapsed / c.network.SlotDuration())
+	return slot, elapsed - time.Duration(slot)*c.network.SlotDuration(), true
+}
+
+// nextSlot returns the first slot to start after the time and its start time
+func (c *ChainClockService) nextSlot(t time.Time) (uint64, time.Time) {
+	slot, _, ok := c.slotAt(t)
+	if ok {
+		slot++
+	}
+	return slot, c.network.SlotStart(slot)
+}
+
+func (c *ChainClockService) slotEvent(slot uint64) clockCommon.SlotEvent {
+	epoch := c.network.Epoch(slot)
+	return clockCommon.SlotEvent{
+		Slot:      slot,
+		Epoch:     epoch,
+		Fork:      c.network.ForkAtEpoch(epoch),
+		SlotStart: c.network.SlotStart(slot).UnixMilli(),
+	}
+}
+
+func (c *ChainClockService) status(t time.Time) (clockCommon.ClockStatus, error) {
+	slot, intoSlot, ok := c.slotAt(t)
+	if !ok {
+		return clockCommon.ClockStatus{}, errBeforeGenesis
+	}
+	return clockCommon.ClockStatus{
+		SlotEvent:    c.slotEvent(slot),
+		TimeIntoSlot: intoSlot.Milliseconds(),
+	}, nil
+}
+
+// run notifies the modules at the start of every slot and epoch until the service is stopped
+func (c *ChainClockService) run() {
+	defer c.wg.Done()
+
+	for {
+		slot, start := c.nextSlot(time.Now())
+		timer := time.NewTimer(time.Until(start))
+		select {
+		case <-c.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		event := c.slotEvent(slot)
+		log := c.log.WithFields(logrus.Fields{
+			"slot":  event.Slot,
+			"epoch": event.Epoch,
+		})
+		log.Debug("new slot")
+
+		if err := c.coreClient.Notify(context.Background(), "core_newSlot", true, nil, event); err != nil {
+			log.WithError(err).Warn("failed to notify modules of the new slot")
+		}
+		if slot%c.network.SlotsPerEpoch == 0 {
+			if err := c.coreClient.Notify(context.Background(), "core_newEpoch", true, nil, event); err != nil {
+				log.WithError(err).Warn("failed to notify modules of the new epoch")
+			}
+		}
+	}
+}
+
+// Status returns the current slot, epoch and fork, and the time elapsed in the slot
+func (c *ChainClockService) Status() (clockCommon.ClockStatus, error) {
+	return c.status(time.Now())
+}
+
+// CurrentSlot returns the current slot
+func (c *ChainClockService) CurrentSlot() (uint64, error) {
+	status, err := c.Status()
+	return status.Slot, err
+}
+
+// CurrentEpoch returns the current epoch
+func (c *ChainClockService) CurrentEpoch() (uint64, error) {
+	status, err := c.Status()
+	return status.Epoch, err
+}
+
+// CurrentFork returns the fork active at the current epoch, the genesis fork before genesis
+func (c *ChainClockService) CurrentFork() network.Fork {
+	slot, _, _ := c.slotAt(time.Now())
+	return c.network.ForkAtEpoch(c.network.Epoch(slot))
+}
+
+// TimeIntoSlot returns the time elapsed since the start of the current slot in milliseconds
+func (c *ChainClockService) TimeIntoSlot() (int64, error) {
+	status, err := c.Status()
+	return status.TimeIntoSlot, err
+}
+
+// SlotStartTime returns the start time of the slot in unix milliseconds
+func (c *ChainClockService) SlotStartTime(slot uint64) int64 {
+	return c.network.SlotStart(slot).UnixMilli()
+}
+
+// Network returns the parameters of the network the clock follows
+func (c *ChainClockService) Network() network.Network {
+	return *c.network
+}
@@ -0,0 +1,75 @@
+package chainclock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
+	"github.com/pon-network/mev-plus/modules/chain-clock/config"
+)
+
+func TestClock(t *testing.T) {
+	c := NewChainClockService()
+	err := c.Configure(common.ModuleFlags{
+		config.NetworkFlag.Name:     network.Holesky,
+		config.GenesisTimeFlag.Name: "1000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.status(time.Unix(999, 0)); err != errBeforeGenesis {
+		t.Errorf("Expected no status before genesis, got %v", err)
+	}
+	if slot, start := c.nextSlot(time.Unix(999, 0)); slot != 0 || start.Unix() != 1000 {
+		t.Errorf("Expected slot 0 at genesis next, got slot %d at %d", slot, start.Unix())
+	}
+
+	// slot 8192 is the first slot of epoch 256, the capella fork of holesky
+	at := time.Unix(1000+8192*12+5, 0)
+	status, err := c.status(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Slot != 8192 || status.Epoch != 256 || status.Fork.Name != "capella" {
+		t.Errorf("Expected slot 8192 of epoch 256 in capella, got slot %d of epoch %d in %s", status.Slot, status.Epoch, status.Fork.Name)
+	}
+	if status.TimeIntoSlot != 5000 || status.SlotStart != (1000+8192*12)*1000 {
+		t.Errorf("Expected 5s into the slot started at %d, got %dms into %d", (1000+8192*12)*1000, status.TimeIntoSlot, status.SlotStart)
+	}
+	if slot, start := c.nextSlot(at); slot != 8193 || start.Unix() != 1000+8193*12 {
+		t.Errorf("Expected slot 8193 next, got slot %d at %d", slot, start.Unix())
+	}
+	if slot, _ := c.nextSlot(time.Unix(1000+8193*12, 0)); slot != 8194 {
+		t.Errorf("Expected the slot after a slot that just started to be next, got %d", slot)
+	}
+
+	if event := c.slotEvent(8191); event.Epoch != 255 || event.Fork.Name != "bellatrix" {
+		t.Errorf("Expected slot 8191 in bellatrix epoch 255, got %s epoch %d", event.Fork.Name, event.Epoch)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	c := NewChainClockService()
+	if c.network.Name != network.Mainnet {
+		t.Errorf("Expected the clock to follow mainnet by default, got %s", c.network.Name)
+	}
+
+	if err := c.Configure(common.ModuleFlags{config.SecondsPerSlotFlag.Name: "6"}); err != nil {
+		t.Fatal(err)
+	}
+	if c.network.SecondsPerSlot != 6 || c.network.GenesisTime != 1606824023 {
+		t.Errorf("Expected mainnet with 6 second slots, got %d second slots from %d", c.network.SecondsPerSlot, c.network.GenesisTime)
+	}
+
+	for _, flags := range []common.ModuleFlags{
+		{config.NetworkFlag.Name: "unknown"},
+		{config.SecondsPerSlotFlag.Name: "0"},
+		{config.GenesisTimeFlag.Name: "-1"},
+	} {
+		if err := NewChainClockService().Configure(flags); err == nil {
+			t.Errorf("Expected an error configuring %v", flags)
+		}
+	}
+}
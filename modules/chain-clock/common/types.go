@@ -0,0 +1,20 @@
+package common
+
+import "github.com/pon-network/mev-plus/common/network"
+
+// SlotEvent is the payload of the core_newSlot and core_newEpoch events, sent at the start of every slot
+// and of every epoch. Modules receive them by implementing NewSlot and NewEpoch methods taking the event.
+type SlotEvent struct {
+	Slot  uint64       `json:"slot,string"`
+	Epoch uint64       `json:"epoch,string"`
+	Fork  network.Fork `json:"fork"`
+	// SlotStart is the start time of the slot in unix milliseconds
+	SlotStart int64 `json:"slot_start"`
+}
+
+// ClockStatus is the position of the clock in the current slot
+type ClockStatus struct {
+	SlotEvent
+	// TimeIntoSlot is the time elapsed since the start of the slot in milliseconds
+	TimeIntoSlot int64 `json:"time_into_slot"`
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"github.com/pon-network/mev-plus/cmd/utils"
+	cli "github.com/urfave/cli/v2"
+)
+
+const ModuleName = "chainClock"
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:      ModuleName,
+		Usage:     "Start the chain clock module",
+		UsageText: "The chain clock module is a service that provides the current slot, epoch and fork of the beacon chain and notifies modules of new slots and epochs",
+		Category:  utils.ChainClockCategory,
+		Flags:     chainClockFlags(),
+	}
+}
+
+func chainClockFlags() []cli.Flag {
+	return []cli.Flag{
+		LoggerLevelFlag,
+		LoggerFormatFlag,
+		NetworkFlag,
+		GenesisTimeFlag,
+		SecondsPerSlotFlag,
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import "github.com/pon-network/mev-plus/common/network"
+
+type ChainClockConfig struct {
+	LoggerLevel    string
+	LoggerFormat   string
+	Network        string
+	GenesisTime    uint64 // overrides the genesis time of the network if set
+	SecondsPerSlot uint64 // overrides the slot duration of the network if set
+}
+
+var ChainClockConfigDefaults = ChainClockConfig{
+	LoggerLevel:    "info",
+	LoggerFormat:   "text",
+	Network:        network.Mainnet,
+	GenesisTime:    0,
+	SecondsPerSlot: 0,
+}
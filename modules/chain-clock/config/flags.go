@@ -0,0 +1,44 @@
+package config
+
+import (
+	"github.com/pon-network/mev-plus/cmd/utils"
+	cli "github.com/urfave/cli/v2"
+)
+
+var (
+	LoggerLevelFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "logger-level",
+		Usage:    "Set the logger level",
+		Category: utils.ChainClockCategory,
+		Value:    ChainClockConfigDefaults.LoggerLevel,
+		EnvVars:  []string{"CHAINCLOCK_LOGGER_LEVEL"},
+	}
+
+	LoggerFormatFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "logger-format",
+		Usage:    "Set the logger format",
+		Category: utils.ChainClockCategory,
+		Value:    ChainClockConfigDefaults.LoggerFormat,
+		EnvVars:  []string{"CHAINCLOCK_LOGGER_FORMAT"},
+	}
+
+	NetworkFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "network",
+		Usage:    "Set the network from a preset (mainnet, sepolia, goerli, holesky) or a consensus config.yaml path or directory for devnets",
+		Category: utils.ChainClockCategory,
+		Value:    ChainClockConfigDefaults.Network,
+		EnvVars:  []string{"CHAINCLOCK_NETWORK"},
+	}
+
+	GenesisTimeFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "genesis-time",
+		Usage:    "Override the genesis time of the network (in seconds)",
+		Category: utils.ChainClockCategory,
+	}
+
+	SecondsPerSlotFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "seconds-per-slot",
+		Usage:    "Override the slot duration of the network (in seconds)",
+		Category: utils.ChainClockCategory,
+	}
+)
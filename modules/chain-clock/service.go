@@ -0,0 +1,134 @@
+package chainclock
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
+	coreCommon "github.com/pon-network/mev-plus/core/common"
+	"github.com/pon-network/mev-plus/modules/chain-clock/config"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+type ChainClockService struct {
+	log        *logrus.Entry
+	coreClient *coreCommon.Client
+
+	cfg     config.ChainClockConfig
+	network *network.Network
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewChainClockService() *ChainClockService {
+	// the default network is a built-in preset and always loads
+	n, _ := network.Preset(config.ChainClockConfigDefaults.Network)
+
+	return &ChainClockService{
+		log:     logrus.NewEntry(logrus.New()).WithField("moduleExecution", config.ModuleName),
+		cfg:     config.ChainClockConfigDefaults,
+		network: n,
+		stop:    make(chan struct{}),
+	}
+}
+
+func (c *ChainClockService) CliCommand() *cli.Command {
+	return config.NewCommand()
+}
+
+func (c *ChainClockService) Configure(moduleFlags common.ModuleFlags) error {
+
+	for flagName, flagValue := range moduleFlags {
+		switch flagName {
+		case config.LoggerLevelFlag.Name:
+			logLevel, err := logrus.ParseLevel(flagValue)
+			if err != nil {
+				return err
+			}
+			c.log.Logger.SetLevel(logLevel)
+		case config.LoggerFormatFlag.Name:
+			switch flagValue {
+			case "json":
+				c.log.Logger.SetFormatter(&logrus.JSONFormatter{})
+			case "text":
+				c.log.Logger.SetFormatter(&logrus.TextFormatter{})
+			default:
+				return fmt.Errorf("invalid logger format %s", flagValue)
+			}
+		case config.NetworkFlag.Name:
+			c.cfg.Network = flagValue
+		case config.GenesisTimeFlag.Name:
+			genesisTime, err := strconv.ParseUint(flagValue, 10, 64)
+			if err != nil {
+				return fmt.Errorf("-%s: %w", config.GenesisTimeFlag.Name, err)
+			}
+			c.cfg.GenesisTime = genesisTime
+		case config.SecondsPerSlotFlag.Name:
+			secondsPerSlot, err := strconv.ParseUint(flagValue, 10, 64)
+			if err != nil || secondsPerSlot == 0 {
+				return fmt.Errorf("-%s: invalid slot duration %q", config.SecondsPerSlotFlag.Name, flagValue)
+			}
+			c.cfg.SecondsPerSlot = secondsPerSlot
+		default:
+			return fmt.Errorf("invalid flag %s", flagName)
+		}
+	}
+
+	n, err := network.Load(c.cfg.Network)
+	if err != nil {
+		return fmt.Errorf("-%s: %w", config.NetworkFlag.Name, err)
+	}
+	if c.cfg.GenesisTime != 0 {
+		n.GenesisTime = c.cfg.GenesisTime
+	}
+	if c.cfg.SecondsPerSlot != 0 {
+		n.SecondsPerSlot = c.cfg.SecondsPerSlot
+	}
+	if n.SecondsPerSlot == 0 || n.SlotsPerEpoch == 0 {
+		return fmt.Errorf("-%s: network %s has no slot duration or slots per epoch", config.NetworkFlag.Name, n.Name)
+	}
+	c.network = n
+
+	return nil
+}
+
+func (c *ChainClockService) Name() string {
+	return config.ModuleName
+}
+
+func (c *ChainClockService) ConnectCore(coreClient *coreCommon.Client, pingId string) error {
+
+	// this is the first and only time the client is set and doesnt need a mutex
+	c.coreClient = coreClient
+
+	// test a ping to the core server
+	err := c.coreClient.Ping(pingId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ChainClockService) Start() error {
+	c.wg.Add(1)
+	go c.run()
+
+	c.log.WithFields(logrus.Fields{
+		"network":        c.network.Name,
+		"genesisTime":    c.network.GenesisTime,
+		"secondsPerSlot": c.network.SecondsPerSlot,
+	}).Info("Started Chain Clock service")
+
+	return nil
+}
+
+func (c *ChainClockService) Stop() error {
+	close(c.stop)
+	c.wg.Wait()
+	return nil
+}
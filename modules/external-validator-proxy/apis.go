@@ -6,13 +6,18 @@ import (
 	"net/http"
 
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
-	"github.com/sirupsen/logrus"
+	"github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/payload"
 
 	"github.com/attestantio/go-builder-client/spec"
 
 	apiv1 "github.com/attestantio/go-builder-client/api/v1"
 )
 
+const (
+	HeaderEthConsensusVersion = "Eth-Consensus-Version"
+)
+
 const (
 	// Builder API paths
 	pathStatus            = "/eth/v1/builder/status"
@@ -117,6 +122,10 @@ func (p *ExternalValidatorProxyService) GetHeader(slot uint64, parentHash, pubke
 				proxyErrRespCh <- err
 				proxyRespCh <- spec.VersionedSignedBuilderBid{}
 				return
+			} else if code == http.StatusNoContent {
+				proxyRespCh <- spec.VersionedSignedBuilderBid{}
+				proxyErrRespCh <- nil
+				return
 			} else if code != http.StatusOK && response.IsEmpty() {
 				p.log.WithError(err).Warnf("Error while calling proxy's get header endpoint: %s", proxy)
 				proxyErrRespCh <- fmt.Errorf("status code %d", code)
@@ -146,7 +155,7 @@ func (p *ExternalValidatorProxyService) GetHeader(slot uint64, parentHash, pubke
 		// If none of the proxies returns a header, return an error that may have occured
 		// If no error occured, return a generic error
 		if respErr == nil {
-			return res, fmt.Errorf("no header returned")
+			return res, &common.NoContentError{Message: "no header returned"}
 		}
 		return res, respErr
 	}
@@ -206,6 +215,12 @@ func (p *ExternalValidatorProxyService) GetPayload(VersionedSignedBlindedBeaconB
 				p.log.WithError(err).Debugf("Error while extracting baseExecutionPayload from proxy's get payload endpoint: %s", p.cfg.Addresses[i].String())
 				continue
 			}
+			// check the payload is of the fork of the block it unblinds
+			if blockVersion, err := VersionedSignedBlindedBeaconBlock.Version(); err != nil || resp.VersionName != blockVersion {
+				p.log.Debugf("Payload of version %s returned from proxy's get payload endpoint does not match the block: %s", resp.VersionName, p.cfg.Addresses[i].String())
+				continue
+			}
+
 			// check if hash matches
 			if baseExecutionPayload.BlockHash.String() != baseSignedBlindedBeaconBlock.Message.Body.ExecutionPayloadHeader.BlockHash.String() {
 				err := fmt.Errorf("blockHash returned from proxy's get payload endpoint %s does not match the one provided %s", baseExecutionPayload.BlockHash.String(), baseSignedBlindedBeaconBlock.Message.Body.ExecutionPayloadHeader.BlockHash.String())
@@ -213,36 +228,16 @@ func (p *ExternalValidatorProxyService) GetPayload(VersionedSignedBlindedBeaconB
 				continue
 			}
 
-			// ensure all the commitments, proofs and blobs are present
-			blobsBundle := baseExecutionPayload.BlobsBundle
-			if len(blobsBundle.Commitments) != len(blobsBundle.Blobs) || len(blobsBundle.Commitments) != len(blobsBundle.Proofs) {
-				err := fmt.Errorf("commitments, proofs and blobs are not of the same length")
-				p.log.WithFields(
-					logrus.Fields{
-						"commitments": len(blobsBundle.Commitments),
-						"proofs":      len(blobsBundle.Proofs),
-						"blobs":       len(blobsBundle.Blobs),
-					}).WithError(
-					err,
-				).Debugf(
-					"Wrong commitments, proofs and blobs returned from proxy's get payload endpoint: %s", p.cfg.Addresses[i].String(),
-				)
+			// recompute the block hash from the payload contents so a payload that does not match the header it claims is rejected
+			if err := payload.VerifyBlockHash(resp.VersionedExecutionPayload, baseSignedBlindedBeaconBlock.Message.ParentRoot); err != nil {
+				p.log.WithError(err).Debugf("Payload contents returned from proxy's get payload endpoint do not match the block hash: %s", p.cfg.Addresses[i].String())
 				continue
 			}
-			for i, commitment := range blobsBundle.Commitments {
-				if commitment != baseSignedBlindedBeaconBlock.Message.Body.BlobKZGCommitments[i] {
-					err := fmt.Errorf("commitment returned from proxy's get payload endpoint %s does not match the one provided at index %s", commitment.String(), baseSignedBlindedBeaconBlock.Message.Body.BlobKZGCommitments[i].String())
-					p.log.WithFields(
-						logrus.Fields{
-							"commitment": commitment.String(),
-							"provided":   baseSignedBlindedBeaconBlock.Message.Body.BlobKZGCommitments[i].String(),
-						}).WithError(
-						err,
-					).Debugf(
-						"Wrong commitment returned from proxy's get payload endpoint: %s", p.cfg.Addresses[i].String(),
-					)
-					continue
-				}
+
+			// ensure there is a valid blob for each commitment of the block
+			if err := payload.VerifyBlobsBundle(baseExecutionPayload.BlobsBundle, baseSignedBlindedBeaconBlock.Message.Body.BlobKZGCommitments); err != nil {
+				p.log.WithError(err).Debugf("Wrong blobs returned from proxy's get payload endpoint: %s", p.cfg.Addresses[i].String())
+				continue
 			}
 
 			// If any of the proxies returns a payload append it
@@ -127,8 +127,8 @@ func (p *ExternalValidatorProxyService) Start() error {
 		return nil
 	}
 
-	var builderApiAddress string
-	err := p.coreClient.Call(&builderApiAddress, "builderApi_listenAddress", false, nil)
+	var builderApiAddresses []string
+	err := p.coreClient.Call(&builderApiAddresses, "builderApi_listenAddresses", false, nil)
 	if err != nil {
 		return err
 	}
@@ -136,7 +136,7 @@ func (p *ExternalValidatorProxyService) Start() error {
 	var joinedAddresses string
 
 	for _, address := range p.cfg.Addresses {
-		if builderApiAddress == address.String() {
+		if builderApiAddress, ok := common.MatchListenAddress(address, builderApiAddresses); ok {
 			return fmt.Errorf("proxy address %s is the same as the builder api address %s", address.String(), builderApiAddress)
 		}
 		if len(joinedAddresses) > 0 {
@@ -67,6 +67,13 @@ func SendHTTPRequest(ctx context.Context, client http.Client, method, url string
 		return 0, fmt.Errorf("could not prepare request: %w", err)
 	}
 
+	// Versioned requests, such as blinded blocks, name their fork in the consensus version header
+	if versioned, ok := payload.(interface{ Version() (string, error) }); ok {
+		if version, err := versioned.Version(); err == nil {
+			req.Header.Set(HeaderEthConsensusVersion, version)
+		}
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return 0, err
@@ -14,10 +14,6 @@ var (
 	ErrLength = errors.New("invalid length")
 )
 
-const (
-	SlotTimeSecMainnet = 12
-)
-
 func GetEnv(key, defaultValue string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
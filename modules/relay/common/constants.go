@@ -1,11 +0,0 @@
-package common
-
-const (
-	GenesisForkVersionMainnet = "0x00000000"
-	GenesisForkVersionSepolia = "0x90000069"
-	GenesisForkVersionGoerli  = "0x00001020"
-
-	GenesisTimeMainnet uint64 = 1606824023
-	GenesisTimeSepolia uint64 = 1655733600
-	GenesisTimeGoerli  uint64 = 1614588812
-)
\ No newline at end of file
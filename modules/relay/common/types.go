@@ -0,0 +1,111 @@
+package common
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RelayOptions are the settings of a single relay. Unset fields fall back to the relay module settings.
+type RelayOptions struct {
+	// Disabled relays are kept in the relay list but not sent any requests
+	Disabled bool `json:"disabled,omitempty"`
+	// RequestTimeoutMs is the timeout of requests to the relay in milliseconds
+	RequestTimeoutMs int `json:"request_timeout_ms,omitempty"`
+	// RequestMaxRetries is the number of attempts of retried requests to the relay, such as getPayload
+	RequestMaxRetries int `json:"request_max_retries,omitempty"`
+	// RetryDelayMs is the delay between attempts of retried requests in milliseconds
+	RetryDelayMs int `json:"retry_delay_ms,omitempty"`
+	// MinBid is the minimum bid value accepted from the relay in wei
+	MinBid string `json:"min_bid,omitempty"`
+	// Headers are HTTP headers sent with every request to the relay, such as API keys
+	Headers map[string]string `json:"headers,omitempty"`
+	// Priority breaks ties between bids of equal value, the bid of the relay with the higher priority is used
+	Priority int `json:"priority,omitempty"`
+}
+
+// Validate checks the options are in range
+func (o RelayOptions) Validate() error {
+	if o.RequestTimeoutMs < 0 {
+		return fmt.Errorf("invalid request timeout %d", o.RequestTimeoutMs)
+	}
+	if o.RequestMaxRetries < 0 {
+		return fmt.Errorf("invalid request max retries %d", o.RequestMaxRetries)
+	}
+	if o.RetryDelayMs < 0 {
+		return fmt.Errorf("invalid retry delay %d", o.RetryDelayMs)
+	}
+	if o.MinBid != "" {
+		if _, ok := o.MinBidValue(); !ok {
+			return fmt.Errorf("invalid min bid %s", o.MinBid)
+		}
+	}
+	for name, value := range o.Headers {
+		if !validHeaderName(name) || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header %q", name)
+		}
+	}
+	return nil
+}
+
+// validHeaderName reports whether the name is an HTTP token
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// MinBidValue returns the min bid of the relay, false if it is unset or invalid
+func (o RelayOptions) MinBidValue() (*big.Int, bool) {
+	if o.MinBid == "" {
+		return nil, false
+	}
+	minBid, ok := new(big.Int).SetString(o.MinBid, 10)
+	if !ok || minBid.Sign() < 0 {
+		return nil, false
+	}
+	return minBid, true
+}
+
+// RelayInfo describes a relay of the relay module
+type RelayInfo struct {
+	URL       string       `json:"url"`
+	PublicKey string       `json:"public_key"`
+	Options   RelayOptions `json:"options"` // without the headers, which may hold credentials
+	// HeaderNames are the names of the headers sent to the relay, their values are not listed
+	HeaderNames []string `json:"header_names,omitempty"`
+}
+
+// Kinds of relay health events
+const (
+	HealthEventFailure = "failure" // a status check of the relay failed
+	HealthEventDown    = "down"    // the relay went down
+	HealthEventUp      = "up"      // the relay came back up
+)
+
+// HealthEvent is a failed status check or an up or down transition of a relay
+type HealthEvent struct {
+	Time   int64  `json:"time"` // unix milliseconds
+	Kind   string `json:"kind"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// RelayHealth is the status check history of a relay, polled by the relay health monitor
+type RelayHealth struct {
+	URL          string        `json:"url"`
+	Up           bool          `json:"up"`
+	Checks       uint64        `json:"checks"`
+	Failures     uint64        `json:"failures"`
+	LastCheck    int64         `json:"last_check"` // unix milliseconds
+	LastFailure  string        `json:"last_failure,omitempty"`
+	LatencyP50Ms int64         `json:"latency_p50_ms"`
+	LatencyP90Ms int64         `json:"latency_p90_ms"`
+	LatencyP99Ms int64         `json:"latency_p99_ms"`
+	Events       []HealthEvent `json:"events"` // most recent events, oldest first
+}
@@ -22,8 +22,10 @@ func relayModulFlags() []cli.Flag {
 		LoggerLevelFlag,
 		LoggerFormatFlag,
 		RelayEntriesFlag,
+		RelayConfigFlag,
 		RelayCheckFlag,
 		SkipRelaySignatureCheck,
+		NetworkFlag,
 		MainnetFlag,
 		SepoliaFlag,
 		GoerliFlag,
@@ -33,5 +35,7 @@ func relayModulFlags() []cli.Flag {
 		GenesisTimeFlag,
 		RequestTimeoutMsFlag,
 		RequestMaxRetriesFlag,
+		HealthCheckIntervalMsFlag,
+		HealthCheckFailureThresholdFlag,
 	}
 }
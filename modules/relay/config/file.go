@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pon-network/mev-plus/modules/relay/common"
+)
+
+// RelayFile is the JSON relay config file listing the relays and their options
+type RelayFile struct {
+	Relays []RelayFileEntry `json:"relays"`
+}
+
+// RelayFileEntry is a relay of the relay config file, with the URL in the format of the relay entries flag
+type RelayFileEntry struct {
+	URL     string              `json:"url"`
+	Options common.RelayOptions `json:"options"`
+}
+
+// LoadRelayFile reads the relay config file at path. A missing file is an empty relay list,
+// so the file can be created by the relays added at runtime.
+func LoadRelayFile(path string) (*RelayFile, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &RelayFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var file RelayFile
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid relay config %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Save writes the relay config file to path, replacing the previous file only once fully written
+func (f *RelayFile) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
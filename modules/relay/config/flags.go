@@ -24,10 +24,17 @@ var (
 
 	RelayEntriesFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "relay-entries",
-		Usage:    "Set the relay entries",
+		Usage:    "Set the relay entries, comma separated, with relay options as query parameters such as ?request_timeout_ms=2000&priority=1&header=X-Api-Key:KEY",
 		Category: utils.RelayModuleCategory,
 	}
 
+	RelayConfigFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "relay-config",
+		Usage:    "Set the path of a JSON relay config file listing relays and their options, relays added, removed or changed at runtime are saved to it",
+		Category: utils.RelayModuleCategory,
+		EnvVars:  []string{"RELAY_CONFIG"},
+	}
+
 	RelayCheckFlag = &cli.BoolFlag{
 		Name:     ModuleName + "." + "relay-check",
 		Usage:    "On status check, check the status of all relays",
@@ -37,9 +44,15 @@ var (
 
 	SkipRelaySignatureCheck = &cli.BoolFlag{
 		Name:     ModuleName + "." + "skip-relay-signature-check",
-		Usage:    "Skip the relay signature check",
+		Usage:    "Skip verifying that bids are signed by the relay with the builder domain of the network",
+		Category: utils.RelayModuleCategory,
+		Value:    !RelayConfigDefaults.RelaySignatureCheck,
+	}
+
+	NetworkFlag = &cli.StringFlag{
+		Name:     ModuleName + "." + "network",
+		Usage:    "Set the network from a preset (mainnet, sepolia, goerli, holesky) or a consensus config.yaml path or directory for devnets",
 		Category: utils.RelayModuleCategory,
-		Value:    true,
 	}
 
 	MainnetFlag = &cli.BoolFlag{
@@ -79,14 +92,14 @@ var (
 
 	GenesisValidatorsRootFlag = &cli.StringFlag{
 		Name:     ModuleName + "." + "genesis-validators-root",
-		Usage:    "Set a custom genesis validators root",
+		Usage:    "Set a custom genesis validators root for the builder signing domain, which is zero on networks following the builder specs",
 		Category: utils.RelayModuleCategory,
 		Value:    RelayConfigDefaults.GenesisValidatorsRoot,
 	}
 
 	GenesisTimeFlag = &cli.IntFlag{
 		Name:     ModuleName + "." + "genesis-time",
-		Usage:    "Set a custom genesis time",
+		Usage:    "Set a custom genesis time, along with a custom fork version. Slot times are taken from the chain clock module",
 		Category: utils.RelayModuleCategory,
 		Value:    int(RelayConfigDefaults.GenesisTime),
 	}
@@ -98,6 +111,20 @@ var (
 		Value:    RelayConfigDefaults.RequestTimeoutMs,
 	}
 
+	HealthCheckIntervalMsFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "health-check-interval-ms",
+		Usage:    "Set the interval in milliseconds of the background status checks of the relays, relays found down are skipped when requesting headers, 0 to disable",
+		Category: utils.RelayModuleCategory,
+		Value:    RelayConfigDefaults.HealthCheckIntervalMs,
+	}
+
+	HealthCheckFailureThresholdFlag = &cli.IntFlag{
+		Name:     ModuleName + "." + "health-check-failure-threshold",
+		Usage:    "Set the number of consecutive failed status checks after which a relay is down",
+		Category: utils.RelayModuleCategory,
+		Value:    RelayConfigDefaults.HealthCheckFailureThreshold,
+	}
+
 	RequestMaxRetriesFlag = &cli.IntFlag{
 		Name:     ModuleName + "." + "request-max-retries",
 		Usage:    "Set the request max retries",
@@ -12,6 +12,7 @@ var (
 	ErrInvalidInput              = errors.New("Invalid input")
 	ErrIncompletePayload         = errors.New("Missing parts of the request payload from the beacon-node")
 	ErrNoPayloadReceived         = errors.New("No payload received from relay")
+	ErrBlockNotDelivered         = errors.New("No relay accepted the blinded block for publication")
 	ErrMaxRetriesExceeded        = errors.New("max retries exceeded")
 	ErrInvalidTransaction        = errors.New("invalid transaction")
 	ErrPointAtInfinityPubkey     = fmt.Errorf("relay public key cannot be the point-at-infinity")
@@ -22,4 +23,8 @@ var (
 	ErrInvalidPubkey             = errors.New("invalid pubkey")
 	ErrNoSuccessfulRelayResponse = errors.New("no successful relay response")
 	ErrUseLastResponse           = errors.New("net/http: use last response")
+	ErrUnknownProposerRelay      = errors.New("proposer config relay is not a configured relay entry")
+	ErrDuplicateRelay            = errors.New("relay is already a configured relay entry")
+	ErrUnknownRelay              = errors.New("relay is not a configured relay entry")
+	ErrInvalidRelayOptions       = errors.New("invalid relay options")
 )
@@ -1,11 +1,15 @@
 package relay
 
 import (
+	"errors"
 	"fmt"
 
 	apiv1 "github.com/attestantio/go-builder-client/api/v1"
 	"github.com/attestantio/go-builder-client/spec"
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	commonType "github.com/pon-network/mev-plus/common"
+	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
+	"github.com/sirupsen/logrus"
 )
 
 func (r *RelayService) Status() error {
@@ -31,6 +35,9 @@ func (r *RelayService) RegisterValidator(payload []apiv1.SignedValidatorRegistra
 
 func (r *RelayService) GetHeader(slot uint64, parentHash, pubkey string) (res []spec.VersionedSignedBuilderBid, err error) {
 	result, err := r.processGetHeader(slot, parentHash, pubkey)
+	if errors.Is(err, ErrNoBidReceived) {
+		return res, &commonType.NoContentError{Message: err.Error()}
+	}
 	if err != nil {
 		return res, err
 	}
@@ -41,3 +48,81 @@ func (r *RelayService) GetHeader(slot uint64, parentHash, pubkey string) (res []
 func (r *RelayService) GetPayload(VersionedSignedBlindedBeaconBlock *commonTypes.VersionedSignedBlindedBeaconBlock) (versionedExecutionPayload []commonTypes.VersionedExecutionPayloadV2WithVersionName, err error) {
 	return r.processGetPayload(*VersionedSignedBlindedBeaconBlock)
 }
+
+func (r *RelayService) SubmitBlindedBlock(VersionedSignedBlindedBeaconBlock *commonTypes.VersionedSignedBlindedBeaconBlock) error {
+	return r.processSubmitBlindedBlock(*VersionedSignedBlindedBeaconBlock)
+}
+
+// AddRelay adds a relay to the relay list while mevPlus is running, the relay URL in the format of the relay entries flag
+func (r *RelayService) AddRelay(relayURL string, options relayCommon.RelayOptions) error {
+	entry, err := parseRelayCall(relayURL)
+	if err != nil {
+		return err
+	}
+	entry.Options = options
+
+	var builderApiAddresses []string
+	if err := r.coreClient.Call(&builderApiAddresses, "builderApi_listenAddresses", false, nil); err != nil {
+		return err
+	}
+	if builderApiAddress, ok := commonType.MatchListenAddress(entry.URL, builderApiAddresses); ok {
+		return &commonType.InvalidParamsError{Message: fmt.Sprintf("relay address %s is the same as the builder api address %s", entry.URL.Host, builderApiAddress)}
+	}
+
+	if err := r.addRelay(entry); err != nil {
+		if errors.Is(err, ErrDuplicateRelay) || errors.Is(err, ErrInvalidRelayOptions) {
+			return &commonType.InvalidParamsError{Message: err.Error()}
+		}
+		return err
+	}
+
+	r.log.WithField("relay", entry.String()).Info("Added relay")
+	return nil
+}
+
+// RemoveRelay removes a relay from the relay list while mevPlus is running, along with the bids only it delivered
+func (r *RelayService) RemoveRelay(relayURL string) error {
+	entry, err := parseRelayCall(relayURL)
+	if err != nil {
+		return err
+	}
+
+	if err := r.removeRelay(entry); err != nil {
+		if errors.Is(err, ErrUnknownRelay) {
+			return &commonType.InvalidParamsError{Message: err.Error()}
+		}
+		return err
+	}
+	return nil
+}
+
+// ListRelays returns the relays of the relay list with their options
+func (r *RelayService) ListRelays() ([]relayCommon.RelayInfo, error) {
+	return r.listRelays(), nil
+}
+
+// SetRelayOptions replaces the options of a relay of the relay list, such as disabling it
+func (r *RelayService) SetRelayOptions(relayURL string, options relayCommon.RelayOptions) error {
+	entry, err := parseRelayCall(relayURL)
+	if err != nil {
+		return err
+	}
+
+	if err := r.setRelayOptions(entry, options); err != nil {
+		if errors.Is(err, ErrUnknownRelay) || errors.Is(err, ErrInvalidRelayOptions) {
+			return &commonType.InvalidParamsError{Message: err.Error()}
+		}
+		return err
+	}
+
+	r.log.WithFields(logrus.Fields{
+		"relay":    entry.String(),
+		"disabled": options.Disabled,
+	}).Info("Set relay options")
+	return nil
+}
+
+// Health returns the status check history of the relays, polled by the relay health monitor
+func (r *RelayService) Health() ([]relayCommon.RelayHealth, error) {
+	return r.health.status(), nil
+}
@@ -0,0 +1,238 @@
+package relay
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+
+	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
+)
+
+const (
+	// healthLatencySamples is the number of most recent status check latencies the percentiles are computed over
+	healthLatencySamples = 128
+	// healthEvents is the number of most recent health events kept for each relay
+	healthEvents = 64
+)
+
+// healthMetrics publishes the health of each relay with expvar
+var healthMetrics = expvar.NewMap("relay.health")
+
+// relayHealth is the status check history of a relay
+type relayHealth struct {
+	checked     bool
+	up          bool
+	checks      uint64
+	failures    uint64
+	consecutive uint64 // failed checks since the last successful one
+	lastCheck   time.Time
+	lastFailure string
+
+	latencies   []time.Duration // ring buffer of the most recent latencies
+	nextLatency int
+	events      []relayCommon.HealthEvent // ring buffer of the most recent events
+	nextEvent   int
+	metrics     *expvar.Map
+}
+
+// healthMonitor tracks the status checks of each relay, keyed by relay URL
+type healthMonitor struct {
+	mu     sync.Mutex
+	relays map[string]*relayHealth
+
+	// failureThreshold is the number of consecutive failed checks after which a relay is down
+	failureThreshold uint64
+}
+
+func newHealthMonitor(failureThreshold uint64) *healthMonitor {
+	return &healthMonitor{relays: make(map[string]*relayHealth), failureThreshold: failureThreshold}
+}
+
+// setFailureThreshold sets the number of consecutive failed checks after which a relay is down
+func (m *healthMonitor) setFailureThreshold(failureThreshold uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failureThreshold = failureThreshold
+}
+
+func (m *healthMonitor) get(relay RelayEntry) *relayHealth {
+	key := relay.String()
+	health, ok := m.relays[key]
+	if !ok {
+		health = &relayHealth{metrics: new(expvar.Map).Init()}
+		m.relays[key] = health
+		healthMetrics.Set(key, health.metrics)
+	}
+	return health
+}
+
+// record records the outcome of a status check of the relay, noting failures and up or down transitions
+func (m *healthMonitor) record(relay RelayEntry, at time.Time, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health := m.get(relay)
+	wasUp := !health.checked || health.up
+
+	health.checked = true
+	health.checks++
+	health.lastCheck = at
+	health.addLatency(latency)
+
+	health.consecutive++
+	if err == nil {
+		health.consecutive = 0
+	}
+	health.up = health.consecutive < m.failureThreshold
+
+	if err != nil {
+		health.failures++
+		health.lastFailure = err.Error()
+		health.addEvent(relayCommon.HealthEvent{Time: at.UnixMilli(), Kind: relayCommon.HealthEventFailure, Reason: err.Error()})
+	}
+	if wasUp && !health.up {
+		health.addEvent(relayCommon.HealthEvent{Time: at.UnixMilli(), Kind: relayCommon.HealthEventDown, Reason: err.Error()})
+	} else if !wasUp && health.up {
+		health.addEvent(relayCommon.HealthEvent{Time: at.UnixMilli(), Kind: relayCommon.HealthEventUp})
+	}
+
+	health.publish()
+}
+
+// isDown reports whether the last status checks of the relay failed, as many as the failure threshold.
+// Relays not checked yet are up.
+func (m *healthMonitor) isDown(relay RelayEntry) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health, ok := m.relays[relay.String()]
+	return ok && health.checked && !health.up
+}
+
+// available returns the relays that are not down, all the relays if every relay is down
+func (m *healthMonitor) available(relays []RelayEntry) []RelayEntry {
+	up := make([]RelayEntry, 0, len(relays))
+	for _, relay := range relays {
+		if !m.isDown(relay) {
+			up = append(up, relay)
+		}
+	}
+	if len(up) == 0 {
+		return relays
+	}
+	return up
+}
+
+// remove drops the history of a relay removed from the relay list
+func (m *healthMonitor) remove(relay RelayEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.relays, relay.String())
+	healthMetrics.Delete(relay.String())
+}
+
+func (m *healthMonitor) status() []relayCommon.RelayHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]relayCommon.RelayHealth, 0, len(m.relays))
+	for url, health := range m.relays {
+		p50, p90, p99 := health.latencyPercentiles()
+		statuses = append(statuses, relayCommon.RelayHealth{
+			URL:          url,
+			Up:           !health.checked || health.up,
+			Checks:       health.checks,
+			Failures:     health.failures,
+			LastCheck:    health.lastCheck.UnixMilli(),
+			LastFailure:  health.lastFailure,
+			LatencyP50Ms: p50.Milliseconds(),
+			LatencyP90Ms: p90.Milliseconds(),
+			LatencyP99Ms: p99.Milliseconds(),
+			Events:       health.orderedEvents(),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].URL < statuses[j].URL })
+
+	return statuses
+}
+
+func (h *relayHealth) addLatency(latency time.Duration) {
+	if len(h.latencies) < healthLatencySamples {
+		h.latencies = append(h.latencies, latency)
+		return
+	}
+	h.latencies[h.nextLatency] = latency
+	h.nextLatency = (h.nextLatency + 1) % healthLatencySamples
+}
+
+func (h *relayHealth) addEvent(event relayCommon.HealthEvent) {
+	if len(h.events) < healthEvents {
+		h.events = append(h.events, event)
+		return
+	}
+	h.events[h.nextEvent] = event
+	h.nextEvent = (h.nextEvent + 1) % healthEvents
+}
+
+// orderedEvents returns a copy of the events, oldest first
+func (h *relayHealth) orderedEvents() []relayCommon.HealthEvent {
+	events := make([]relayCommon.HealthEvent, 0, len(h.events))
+	events = append(events, h.events[h.nextEvent:]...)
+	return append(events, h.events[:h.nextEvent]...)
+}
+
+// latencyPercentiles returns the 50th, 90th and 99th percentile of the recent status check latencies
+func (h *relayHealth) latencyPercentiles() (p50, p90, p99 time.Duration) {
+	if len(h.latencies) == 0 {
+		return 0, 0, 0
+	}
+	sorted := make([]time.Duration, len(h.latencies))
+	copy(sorted, h.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	return percentile(50), percentile(90), percentile(99)
+}
+
+func (h *relayHealth) publish() {
+	up := new(expvar.Int)
+	if h.up {
+		up.Set(1)
+	}
+	h.metrics.Set("up", up)
+	h.metrics.Set("checks", expvarInt(h.checks))
+	h.metrics.Set("failures", expvarInt(h.failures))
+
+	p50, p90, p99 := h.latencyPercentiles()
+	h.metrics.Set("latency_p50_ms", expvarInt(uint64(p50.Milliseconds())))
+	h.metrics.Set("latency_p90_ms", expvarInt(uint64(p90.Milliseconds())))
+	h.metrics.Set("latency_p99_ms", expvarInt(uint64(p99.Milliseconds())))
+}
+
+func expvarInt(value uint64) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(int64(value))
+	return v
+}
+
+// runHealthMonitor checks the status of the enabled relays every interval until the relay service is stopped
+func (r *RelayService) runHealthMonitor(interval time.Duration) {
+	defer r.healthWg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopHealth:
+			return
+		case <-ticker.C:
+			r.checkRelays()
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package relay
+
+import (
+	"errors"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commonType "github.com/pon-network/mev-plus/common"
+	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
+	"github.com/pon-network/mev-plus/modules/relay/config"
+)
+
+func TestHealthMonitor(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+	_, otherPubkey := testSecretKey(t, 2)
+	relay, _ := NewRelayEntry("http://" + pubkey.String() + "@localhost:18550")
+	other, _ := NewRelayEntry("http://" + otherPubkey.String() + "@localhost:18551")
+
+	m := newHealthMonitor(2)
+	if m.isDown(relay) {
+		t.Error("Expected a relay not checked yet to be up")
+	}
+
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		m.record(relay, now, time.Duration(i)*time.Millisecond, nil)
+	}
+	m.record(relay, now, time.Millisecond, errors.New("connection refused"))
+	if m.isDown(relay) {
+		t.Error("Expected one failed check not to take the relay down")
+	}
+	if relays := m.available([]RelayEntry{relay, other}); len(relays) != 2 {
+		t.Errorf("Expected the relay with one failed check to be used, got %v", RelayEntriesToStrings(relays))
+	}
+	m.record(relay, now, time.Millisecond, errors.New("connection refused"))
+	if !m.isDown(relay) {
+		t.Error("Expected the relay to be down after consecutive failed checks")
+	}
+
+	// Every relay down falls back to all relays
+	if relays := m.available([]RelayEntry{relay}); len(relays) != 1 {
+		t.Error("Expected all relays to be used when every relay is down")
+	}
+	if relays := m.available([]RelayEntry{relay, other}); len(relays) != 1 || !relays[0].sameRelay(other) {
+		t.Errorf("Expected the down relay to be skipped, got %v", RelayEntriesToStrings(relays))
+	}
+
+	m.record(relay, now, time.Millisecond, nil)
+
+	status := m.status()
+	if len(status) != 1 {
+		t.Fatalf("Expected the health of one relay, got %d", len(status))
+	}
+	health := status[0]
+	if !health.Up || health.Checks != 103 || health.Failures != 2 || health.LastFailure != "connection refused" {
+		t.Errorf("Unexpected relay health %+v", health)
+	}
+	if health.LatencyP50Ms != 49 || health.LatencyP99Ms != 98 {
+		t.Errorf("Unexpected latency percentiles p50 %d p99 %d", health.LatencyP50Ms, health.LatencyP99Ms)
+	}
+
+	kinds := make([]string, 0, len(health.Events))
+	for _, event := range health.Events {
+		kinds = append(kinds, event.Kind)
+	}
+	expected := []string{relayCommon.HealthEventFailure, relayCommon.HealthEventFailure, relayCommon.HealthEventDown, relayCommon.HealthEventUp}
+	if strings.Join(kinds, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected events %v, got %v", expected, kinds)
+	}
+
+	metrics, ok := healthMetrics.Get(relay.String()).(*expvar.Map)
+	if !ok || metrics.Get("up").String() != "1" || metrics.Get("failures").String() != "2" {
+		t.Errorf("Expected the relay health to be published, got %v", metrics)
+	}
+
+	m.remove(relay)
+	if len(m.status()) != 0 || healthMetrics.Get(relay.String()) != nil {
+		t.Error("Expected the health of the removed relay to be dropped")
+	}
+}
+
+func TestHealthFailureThreshold(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+	relay, _ := NewRelayEntry("http://" + pubkey.String() + "@localhost:18550")
+
+	configure := func(r *RelayService, failureThreshold string) error {
+		return r.Configure(commonType.ModuleFlags{
+			config.RelayEntriesFlag.Name:                relay.String(),
+			config.HealthCheckFailureThresholdFlag.Name: failureThreshold,
+		})
+	}
+	if err := configure(NewRelayService(), "0"); err == nil {
+		t.Error("Expected a failure threshold below one to be refused")
+	}
+	r := NewRelayService()
+	if err := configure(r, "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A success resets the consecutive failures
+	now := time.Now()
+	for _, err := range []error{errors.New("timeout"), errors.New("timeout"), nil, errors.New("timeout"), errors.New("timeout")} {
+		r.health.record(relay, now, time.Millisecond, err)
+	}
+	if r.health.isDown(relay) {
+		t.Error("Expected the relay to be up below the failure threshold")
+	}
+	r.health.record(relay, now, time.Millisecond, errors.New("timeout"))
+	if !r.health.isDown(relay) {
+		t.Error("Expected the relay to be down at the failure threshold")
+	}
+}
+
+func TestHealthEventsRingBuffer(t *testing.T) {
+	var h relayHealth
+	for i := 0; i < healthEvents+10; i++ {
+		h.addEvent(relayCommon.HealthEvent{Time: int64(i)})
+	}
+
+	events := h.orderedEvents()
+	if len(events) != healthEvents {
+		t.Fatalf("Expected %d events, got %d", healthEvents, len(events))
+	}
+	for i, event := range events {
+		if event.Time != int64(i+10) {
+			t.Fatalf("Expected the most recent events oldest first, got %d at %d", event.Time, i)
+		}
+	}
+}
+
+func TestHealthMonitorChecks(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	r := NewRelayService()
+	r.log.Logger.SetOutput(io.Discard)
+	relay, err := NewRelayEntry(strings.Replace(server.URL, "http://", "http://"+pubkey.String()+"@", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.relays = []RelayEntry{relay}
+
+	r.healthWg.Add(1)
+	go r.runHealthMonitor(10 * time.Millisecond)
+	defer r.Stop()
+
+	waitFor := func(up bool) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if health, _ := r.Health(); len(health) == 1 && health[0].Checks > 0 && health[0].Up == up {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Expected the relay up %t", up)
+	}
+
+	waitFor(true)
+	down.Store(true)
+	waitFor(false)
+
+	// Stopping twice does not panic
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	health, _ := r.Health()
+	if !strings.Contains(health[0].LastFailure, "503") {
+		t.Errorf("Expected the failure reason to be recorded, got %q", health[0].LastFailure)
+	}
+}
@@ -2,10 +2,12 @@ package relay
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	apiv1 "github.com/attestantio/go-builder-client/api/v1"
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
@@ -20,24 +22,40 @@ func (r *RelayService) processRegistration(payload []apiv1.SignedValidatorRegist
 		"numRegistrations": len(payload),
 	})
 
+	// Only send each relay the registrations of the validators the proposer config assigns to it
+	relayRegistrations := make(map[string][]apiv1.SignedValidatorRegistration)
+	for _, registration := range payload {
+		for _, relay := range r.relaysForProposer(registration.Message.Pubkey.String()) {
+			relayRegistrations[relay.String()] = append(relayRegistrations[relay.String()], registration)
+		}
+	}
+	if len(relayRegistrations) == 0 {
+		return ErrNoRelays
+	}
+
 	var respErr error
-	relayRespCh := make(chan error, len(r.relays))
+	relayRespCh := make(chan error, len(relayRegistrations))
 
-	for _, relay := range r.relays {
-		go func(relayEntry RelayEntry) {
+	for _, relay := range r.relayEntries() {
+		registrations, ok := relayRegistrations[relay.String()]
+		if !ok {
+			continue
+		}
+
+		go func(relayEntry RelayEntry, registrations []apiv1.SignedValidatorRegistration) {
 			url := relayEntry.GetURI(pathRegisterValidator)
 			log := log.WithField("url", url)
 
-			_, err := SendHTTPRequest(context.Background(), r.httpClient, http.MethodPost, url, payload, nil)
+			_, err := SendHTTPRequest(context.Background(), r.relayHTTPClient(relayEntry), http.MethodPost, url, relayEntry.Options.Headers, registrations, nil)
 			relayRespCh <- err
 			if err != nil {
 				log.WithError(err).Warn("Error while calling relay's registration endpoint")
 				return
 			}
-		}(relay)
+		}(relay, registrations)
 	}
 
-	for i := 0; i < len(r.relays); i++ {
+	for i := 0; i < len(relayRegistrations); i++ {
 		respErr = <-relayRespCh
 		if respErr == nil {
 			return nil
@@ -65,14 +83,18 @@ func (r *RelayService) processGetHeader(slot uint64, parentHashHex, pubkey strin
 	result := bidResp{}                     // the final response, containing the highest bid (if any)
 	relays := make(map[string][]RelayEntry) // relays that sent the bid for a specific blockHash
 
+	// A min bid in the proposer config replaces the min bid of every relay
+	proposerMinBid := r.proposerConfig.For(pubkey).MinBidValue()
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	for _, relay := range r.relays {
+	// Relays the health monitor found down are skipped
+	for _, relay := range r.health.available(r.relaysForProposer(pubkey)) {
 		wg.Add(1)
 		go func(relay RelayEntry) {
 			defer wg.Done()
-			r.requestRelayHeader(slot, parentHashHex, pubkey, relay, log, &mu, &result, relays)
+			r.requestRelayHeader(slot, parentHashHex, pubkey, r.minBidFor(relay, proposerMinBid), relay, log, &mu, &result, relays)
 		}(relay)
 	}
 	wg.Wait()
@@ -155,13 +177,68 @@ func (r *RelayService) processGetPayload(block commonTypes.VersionedSignedBlinde
 	return res, nil
 }
 
+func (r *RelayService) processSubmitBlindedBlock(block commonTypes.VersionedSignedBlindedBeaconBlock) error {
+	log := r.log.WithField("method", "submitBlindedBlock")
+
+	blockBase, err := block.ToBaseSignedBlindedBeaconBlock()
+	if err != nil {
+		return err
+	}
+
+	if err := validatePayloadBlock(blockBase, log); err != nil {
+		return err
+	}
+
+	logger := log.WithFields(logrus.Fields{
+		"slot":       blockBase.Message.Slot,
+		"blockHash":  blockBase.Message.Body.ExecutionPayloadHeader.BlockHash.String(),
+		"parentHash": blockBase.Message.Body.ExecutionPayloadHeader.ParentHash.String(),
+	})
+
+	r.bidsLock.Lock()
+	originalBid := r.bids[bidRespKey{slot: uint64(blockBase.Message.Slot), blockHash: blockBase.Message.Body.ExecutionPayloadHeader.BlockHash.String()}]
+	r.bidsLock.Unlock()
+
+	if err := validateOriginalBid(logger, originalBid); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var delivered bool
+	var payloadResult commonTypes.VersionedExecutionPayloadV2WithVersionName
+
+	requestCtx, requestCtxCancel := context.WithCancel(context.Background())
+	defer requestCtxCancel()
+
+	// Submit the block to each relay, only one needs to accept it for the block to be published
+	for _, relay := range originalBid.relays {
+		wg.Add(1)
+		go func(relay RelayEntry) {
+			defer wg.Done()
+			r.submitRelayBlindedBlock(relay, logger, &block, &delivered, &payloadResult, &mu, requestCtx, requestCtxCancel)
+		}(relay)
+	}
+
+	wg.Wait()
+
+	// Relays without the v2 endpoint publish the block when returning its payload
+	if !delivered && payloadResult == (commonTypes.VersionedExecutionPayloadV2WithVersionName{}) {
+		originRelays := RelayEntriesToStrings(originalBid.relays)
+		logger.WithField("relaysWithBid", strings.Join(originRelays, ", ")).Error("No relay accepted the blinded block!")
+		return ErrBlockNotDelivered
+	}
+
+	return nil
+}
+
 
-// CheckRelays sends a request to each one of the relays previously registered to get their status
+// CheckRelays sends a request to each one of the enabled relays to get their status, recording the outcome in the relay health
 func (r *RelayService) checkRelays() int {
 	var wg sync.WaitGroup
 	var numSuccessRequestsToRelay uint32
 
-	for _, relay := range r.relays {
+	for _, relay := range r.relayEntries() {
 		wg.Add(1)
 
 		go func(relay RelayEntry) {
@@ -170,17 +247,17 @@ func (r *RelayService) checkRelays() int {
 			log := r.log.WithField("url", url)
 			log.Debug("checking relay status")
 
-			code, err := SendHTTPRequest(context.Background(), r.httpClient, http.MethodGet, url, nil, nil)
-			if err != nil {
-				log.WithError(err).Error("relay status error - request failed")
-				return
+			start := time.Now()
+			code, err := SendHTTPRequest(context.Background(), r.relayHTTPClient(relay), http.MethodGet, url, relay.Options.Headers, nil, nil)
+			if err == nil && code != http.StatusOK {
+				err = fmt.Errorf("unexpected status code %d", code)
 			}
-			if code == http.StatusOK {
-				log.Debug("relay status OK")
-			} else {
-				log.Errorf("relay status error - unexpected status code %d", code)
+			r.health.record(relay, start, time.Since(start), err)
+			if err != nil {
+				log.WithError(err).Error("relay status error")
 				return
 			}
+			log.Debug("relay status OK")
 
 			atomic.AddUint32(&numSuccessRequestsToRelay, 1)
 		}(relay)
@@ -0,0 +1,26 @@
+package relay
+
+import (
+	"expvar"
+)
+
+const (
+	// bidPubkeyMismatch is a bid not signed with the pubkey of the relay it came from
+	bidPubkeyMismatch = "pubkey_mismatch"
+	// bidSignatureInvalid is a bid with a signature that does not verify against the relay pubkey
+	bidSignatureInvalid = "signature_invalid"
+	// bidSignatureError is a bid whose signature could not be checked, such as a malformed signature
+	bidSignatureError = "signature_error"
+)
+
+// bidVerificationFailures counts the bids dropped for failing verification, keyed by relay and reason
+// as relay.reason
+var bidVerificationFailures = expvar.NewMap("relay.bidVerificationFailures")
+
+func bidVerificationFailureKey(relay RelayEntry, reason string) string {
+	return relay.String() + "." + reason
+}
+
+func recordBidVerificationFailure(relay RelayEntry, reason string) {
+	bidVerificationFailures.Add(bidVerificationFailureKey(relay, reason), 1)
+}
@@ -4,22 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/attestantio/go-builder-client/spec"
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	"github.com/pon-network/mev-plus/common/payload"
 	"github.com/sirupsen/logrus"
 )
 
-func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey string, relay RelayEntry, log *logrus.Entry, mu *sync.Mutex, result *bidResp, relaysMap map[string][]RelayEntry) {
+func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey string, minBid *big.Int, relay RelayEntry, log *logrus.Entry, mu *sync.Mutex, result *bidResp, relaysMap map[string][]RelayEntry) {
 	path := fmt.Sprintf("/eth/v1/builder/header/%d/%s/%s", slot, parentHashHex, pubkey)
 	url := relay.GetURI(path)
 	log = log.WithField("url", url)
 	responsePayload := new(spec.VersionedSignedBuilderBid)
 
-	code, err := SendHTTPRequest(context.Background(), r.httpClient, http.MethodGet, url, nil, responsePayload)
+	code, err := SendHTTPRequest(context.Background(), r.relayHTTPClient(relay), http.MethodGet, url, relay.Options.Headers, nil, responsePayload)
 	if err != nil {
 		log.WithError(err).Warn("error making request to relay")
 		return
@@ -54,6 +56,7 @@ func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey str
 	})
 
 	if relay.PublicKey.String() != bidInfo.pubkey.String() {
+		recordBidVerificationFailure(relay, bidPubkeyMismatch)
 		log.Errorf("bid pubkey mismatch. expected: %s - got: %s", relay.PublicKey.String(), bidInfo.pubkey.String())
 		return
 	}
@@ -62,11 +65,13 @@ func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey str
 	if r.relaySignatureCheck {
 		ok, err := checkRelaySignature(responsePayload, relay.SigningDomain, relay.PublicKey)
 		if err != nil {
-			log.WithError(err).Error("error verifying relay signature")
+			recordBidVerificationFailure(relay, bidSignatureError)
+			log.WithError(err).WithField("version", responsePayload.Version.String()).Error("error verifying relay signature")
 			return
 		}
 		if !ok {
-			log.Error("failed to verify relay signature")
+			recordBidVerificationFailure(relay, bidSignatureInvalid)
+			log.WithField("version", responsePayload.Version.String()).Error("failed to verify relay signature")
 			return
 		}
 	}
@@ -86,7 +91,7 @@ func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey str
 	}
 	log.Debug("bid received")
 
-	if bidInfo.value.CmpBig(r.relayMinBid.BigInt()) == -1 {
+	if bidInfo.value.CmpBig(minBid) == -1 {
 		log.Debug("ignoring bid below min-bid value")
 		return
 	}
@@ -102,9 +107,12 @@ func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey str
 		valueDiff := bidInfo.value.Cmp(result.bidInfo.value)
 		if valueDiff == -1 { // current bid is less profitable than already known one
 			return
-		} else if valueDiff == 0 { // current bid is equally profitable as already known one. Use hash as tiebreaker
+		} else if valueDiff == 0 { // current bid is equally profitable as already known one. Use relay priority, then hash as tiebreaker
 			previousBidBlockHash := result.bidInfo.blockHash
-			if bidInfo.blockHash.String() >= previousBidBlockHash.String() {
+			if bidInfo.blockHash == previousBidBlockHash || relay.Options.Priority < result.priority {
+				return
+			}
+			if relay.Options.Priority == result.priority && bidInfo.blockHash.String() >= previousBidBlockHash.String() {
 				return
 			}
 		}
@@ -114,6 +122,7 @@ func (r *RelayService) requestRelayHeader(slot uint64, parentHashHex, pubkey str
 	log.Debug("new best bid")
 	result.response = *responsePayload
 	result.bidInfo = bidInfo
+	result.priority = relay.Options.Priority
 	result.t = time.Now()
 }
 
@@ -124,7 +133,8 @@ func (r *RelayService) requestRelayPayload(relay RelayEntry, logger *logrus.Entr
 	logger.Debug("calling getPayload")
 
 	responsePayload := new(commonTypes.VersionedExecutionPayloadV2WithVersionName)
-	_, err := SendHTTPRequestWithRetries(requestCtx, r.httpClient, http.MethodPost, url, block, responsePayload, r.cfg.RequestMaxRetries, logger)
+	maxRetries, retryDelay := r.relayRetryPolicy(relay)
+	_, err := SendHTTPRequestWithRetries(requestCtx, r.relayHTTPClient(relay), http.MethodPost, url, relay.Options.Headers, block, responsePayload, maxRetries, retryDelay, logger)
 
 	if err != nil {
 		if errors.Is(requestCtx.Err(), context.Canceled) {
@@ -147,6 +157,12 @@ func (r *RelayService) requestRelayPayload(relay RelayEntry, logger *logrus.Entr
 		return
 	}
 
+	// Ensure the payload is of the fork of the block it unblinds
+	if blockVersion, err := block.Version(); err != nil || responsePayload.VersionName != blockVersion {
+		logger.WithField("responseVersion", responsePayload.VersionName).Error("Response version does not match the block version")
+		return
+	}
+
 	// Ensure the response blockhash matches the request
 	if blockBase.Message.Body.ExecutionPayloadHeader.BlockHash != responsePayloadBase.BlockHash {
 		logger.WithFields(logrus.Fields{
@@ -155,6 +171,12 @@ func (r *RelayService) requestRelayPayload(relay RelayEntry, logger *logrus.Entr
 		return
 	}
 
+	// Recompute the block hash from the payload contents so a payload that does not match the header it claims is rejected
+	if err := payload.VerifyBlockHash(responsePayload.VersionedExecutionPayload, blockBase.Message.ParentRoot); err != nil {
+		logger.WithError(err).Error("Payload contents do not match the block hash")
+		return
+	}
+
 	// In the case of transaction tampering, check that the response txRoot matches the request
 	computedTxRoot, err := commonTypes.ComputeTransactionsRoot(responsePayloadBase.Transactions)
 	if err != nil {
@@ -182,36 +204,10 @@ func (r *RelayService) requestRelayPayload(relay RelayEntry, logger *logrus.Entr
 		return
 	}
 
-	// ensure all the commitments, proofs and blobs are present
-	blobsBundle := responsePayloadBase.BlobsBundle
-	if len(blobsBundle.Commitments) != len(blobsBundle.Blobs) || len(blobsBundle.Commitments) != len(blobsBundle.Proofs) {
-		err := fmt.Errorf("commitments, proofs and blobs are not of the same length")
-		logger.WithFields(
-			logrus.Fields{
-				"commitments": len(blobsBundle.Commitments),
-				"proofs":      len(blobsBundle.Proofs),
-				"blobs":       len(blobsBundle.Blobs),
-			}).WithError(
-			err,
-		).Errorf(
-			"Wrong commitments, proofs and blobs returned from relay's get payload endpoint: %s", relay.String(),
-		)
-		return
-	}
-	for i, commitment := range blobsBundle.Commitments {
-		if commitment != blockBase.Message.Body.BlobKZGCommitments[i] {
-			err := fmt.Errorf("commitment returned from relay's get payload endpoint %s does not match the one provided at index %s", commitment.String(), blockBase.Message.Body.BlobKZGCommitments[i].String())
-			logger.WithFields(
-				logrus.Fields{
-					"commitment": commitment.String(),
-					"provided":   blockBase.Message.Body.BlobKZGCommitments[i].String(),
-				}).WithError(
-				err,
-			).Errorf(
-				"Wrong commitment returned from relay's get payload endpoint: %s", relay.String(),
-			)
-			continue
-		}
+	// Ensure there is a valid blob for each commitment of the block
+	if err := payload.VerifyBlobsBundle(responsePayloadBase.BlobsBundle, blockBase.Message.Body.BlobKZGCommitments); err != nil {
+		logger.WithError(err).Errorf("Wrong blobs returned from relay's get payload endpoint: %s", relay.String())
+		return
 	}
 
 	mu.Lock()
@@ -230,4 +226,38 @@ func (r *RelayService) requestRelayPayload(relay RelayEntry, logger *logrus.Entr
 
 	*result = *responsePayload
 	logger.Info("Received payload from relay")
-}
\ No newline at end of file
+}
+
+func (r *RelayService) submitRelayBlindedBlock(relay RelayEntry, logger *logrus.Entry, block *commonTypes.VersionedSignedBlindedBeaconBlock, delivered *bool, payloadResult *commonTypes.VersionedExecutionPayloadV2WithVersionName, mu *sync.Mutex, requestCtx context.Context, requestCtxCancel context.CancelFunc) {
+	url := relay.GetURI(pathSubmitBlindedBlockV2)
+	logger = logger.WithField("url", url)
+	logger.Debug("calling submitBlindedBlockV2")
+
+	code, err := SendHTTPRequest(requestCtx, r.relayHTTPClient(relay), http.MethodPost, url, relay.Options.Headers, block, nil)
+	if code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+		// The relay does not support the v2 endpoint, unblinding the block through v1 has it published
+		logger.Debug("Relay does not support blinded block submission v2, falling back to getPayload")
+		r.requestRelayPayload(relay, logger, block, payloadResult, mu, requestCtx, requestCtxCancel)
+		return
+	}
+	if err != nil {
+		if errors.Is(requestCtx.Err(), context.Canceled) {
+			logger.Info("Request was canceled")
+		} else {
+			logger.WithError(err).Error("Error making request to relay")
+		}
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if requestCtx.Err() != nil { // Request has been canceled (or deadline exceeded)
+		return
+	}
+
+	requestCtxCancel()
+
+	*delivered = true
+	logger.WithField("code", code).Info("Relay accepted blinded block for publication")
+}
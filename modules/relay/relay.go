@@ -10,6 +10,7 @@ import (
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	commonType "github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/proposer"
 	coreCommon "github.com/pon-network/mev-plus/core/common"
 	"github.com/pon-network/mev-plus/modules/relay/common"
 	"github.com/pon-network/mev-plus/modules/relay/config"
@@ -19,24 +20,30 @@ import (
 )
 
 const (
-	HeaderKeySlotUID = "X-MEVPLUS-SlotID"
-	HeaderKeyVersion = "X-MEVPLUS-Version"
+	HeaderKeySlotUID          = "X-MEVPLUS-SlotID"
+	HeaderKeyVersion          = "X-MEVPLUS-Version"
+	HeaderEthConsensusVersion = "Eth-Consensus-Version"
 )
 
 var (
 	nilHash = phase0.Hash32{}
+
+	// defaultRetryDelay is the delay between attempts of retried requests to relays without a retry delay option
+	defaultRetryDelay = 100 * time.Millisecond
 )
 
 const (
 	// Router paths
-	pathStatus            = "/eth/v1/builder/status"
-	pathRegisterValidator = "/eth/v1/builder/validators"
-	pathGetHeader         = "/eth/v1/builder/header/{slot:[0-9]+}/{parent_hash:0x[a-fA-F0-9]+}/{pubkey:0x[a-fA-F0-9]+}"
-	pathGetPayload        = "/eth/v1/builder/blinded_blocks"
+	pathStatus               = "/eth/v1/builder/status"
+	pathRegisterValidator    = "/eth/v1/builder/validators"
+	pathGetHeader            = "/eth/v1/builder/header/{slot:[0-9]+}/{parent_hash:0x[a-fA-F0-9]+}/{pubkey:0x[a-fA-F0-9]+}"
+	pathGetPayload           = "/eth/v1/builder/blinded_blocks"
+	pathSubmitBlindedBlockV2 = "/eth/v2/builder/blinded_blocks"
 )
 
 type RelayService struct {
 	relays     []RelayEntry
+	relaysLock sync.RWMutex // relays are added, removed and changed at runtime
 	coreClient *coreCommon.Client
 	cfg        config.RelayConfig
 
@@ -44,11 +51,19 @@ type RelayService struct {
 	relayCheck          bool
 	relaySignatureCheck bool
 	relayMinBid         common.U256Str
-	genesisTime         uint64
+	signingDomain       phase0.Domain // builder domain of the network, set on relays when signatures are checked
+	relayConfigPath     string        // relay config file the relay list is saved to, if one is used
 
 	httpClient http.Client
 	bids       map[bidRespKey]bidResp // keeping track of bids, to log the originating relay on withholding
 	bidsLock   sync.Mutex
+
+	proposerConfig *proposer.Config // per validator relays and min bid, set by the block aggregator
+
+	health     *healthMonitor // status check history of the relays
+	stopHealth chan struct{}
+	stopOnce   sync.Once
+	healthWg   sync.WaitGroup
 }
 
 func NewRelayService() *RelayService {
@@ -62,6 +77,8 @@ func NewRelayService() *RelayService {
 		relayCheck:          config.RelayConfigDefaults.RelayCheck,
 		relaySignatureCheck: config.RelayConfigDefaults.RelaySignatureCheck,
 		bids:                make(map[bidRespKey]bidResp),
+		health:              newHealthMonitor(uint64(config.RelayConfigDefaults.HealthCheckFailureThreshold)),
+		stopHealth:          make(chan struct{}),
 		httpClient:          http.Client{Timeout: time.Duration(config.RelayConfigDefaults.RequestTimeoutMs) * time.Millisecond},
 	}
 }
@@ -93,15 +110,15 @@ func (r *RelayService) Configure(moduleFlags commonType.ModuleFlags) error {
 		return err
 	}
 
+	// Bids are signed by relays with the builder domain of the network, which is only needed to verify them
 	if r.relaySignatureCheck {
 		domain, err := signing.ComputeDomain(signing.DomainTypeAppBuilder, r.cfg.GenesisForkVersion, r.cfg.GenesisValidatorsRoot)
 		if err != nil {
 			return err
 		}
-		var domainPhase0 phase0.Domain
-		copy(domainPhase0[:], domain[:])
-		for _, relayEntry := range r.relays {
-			relayEntry.SigningDomain = domainPhase0
+		r.signingDomain = phase0.Domain(domain)
+		for i := range r.relays {
+			r.relays[i].SigningDomain = r.signingDomain
 		}
 	}
 
@@ -135,15 +152,25 @@ func (r *RelayService) Start() error {
 		operationalRelays = ok
 	}
 
-	var builderApiAddress string
-	err := r.coreClient.Call(&builderApiAddress, "builderApi_listenAddress", false, nil)
+	var builderApiAddresses []string
+	err := r.coreClient.Call(&builderApiAddresses, "builderApi_listenAddresses", false, nil)
 	if err != nil {
 		return err
 	}
 
 	for _, relayEntry := range r.relays {
-		if relayEntry.URL.String() == builderApiAddress {
-			return fmt.Errorf("relay address %s is the same as the builder api address %s", relayEntry.URL.String(), builderApiAddress)
+		if builderApiAddress, ok := commonType.MatchListenAddress(relayEntry.URL, builderApiAddresses); ok {
+			return fmt.Errorf("relay address %s is the same as the builder api address %s", relayEntry.URL.Host, builderApiAddress)
+		}
+	}
+
+	err = r.coreClient.Call(&r.proposerConfig, "blockAggregator_proposerConfig", false, nil)
+	if err != nil {
+		return err
+	}
+	for _, relay := range r.proposerConfig.RelayValues() {
+		if _, err := r.findRelayEntry(relay); err != nil {
+			return err
 		}
 	}
 
@@ -158,10 +185,18 @@ func (r *RelayService) Start() error {
 	r.log.Info("Configured relays: ", strings.Join(RelayEntriesToStrings(r.relays), ", "))
 	r.log.Infof("Using %d operational relays", operationalRelays)
 
+	if r.cfg.HealthCheckIntervalMs > 0 {
+		r.healthWg.Add(1)
+		go r.runHealthMonitor(time.Duration(r.cfg.HealthCheckIntervalMs) * time.Millisecond)
+	}
+
 	return nil
 }
 
 func (r *RelayService) Stop() error {
 
+	r.stopOnce.Do(func() { close(r.stopHealth) })
+	r.healthWg.Wait()
+
 	return nil
 }
@@ -2,20 +2,35 @@ package relay
 
 import (
 	"bytes"
+	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
 )
 
 // The point-at-infinity is 48 zero bytes.
 var pointAtInfinityPubkey = [48]byte{}
 
+// Query parameters of a relay URL setting the relay options
+const (
+	relayOptionDisabled          = "disabled"
+	relayOptionRequestTimeoutMs  = "request_timeout_ms"
+	relayOptionRequestMaxRetries = "request_max_retries"
+	relayOptionRetryDelayMs      = "retry_delay_ms"
+	relayOptionMinBid            = "min_bid"
+	relayOptionHeader            = "header"
+	relayOptionPriority          = "priority"
+)
+
 // RelayEntry represents a relay that mev-plus connects to.
 type RelayEntry struct {
 	PublicKey phase0.BLSPubKey
 	SigningDomain phase0.Domain
 	URL       *url.URL
+	Options   relayCommon.RelayOptions
 }
 
 func (r *RelayEntry) String() string {
@@ -57,9 +72,75 @@ func NewRelayEntry(relayURL string) (entry RelayEntry, err error) {
 		return entry, ErrPointAtInfinityPubkey
 	}
 
+	// Extract the relay's options from the query parameters, if any.
+	entry.Options, err = parseRelayOptions(entry.URL)
+	if err != nil {
+		return entry, err
+	}
+
 	return entry, nil
 }
 
+// parseRelayOptions parses the relay options set as query parameters of the relay URL, such as
+// https://PUBKEY@HOST?request_timeout_ms=2000&priority=1&header=X-Api-Key:KEY, and removes them
+// from the URL. Other query parameters are kept and sent to the relay.
+func parseRelayOptions(u *url.URL) (options relayCommon.RelayOptions, err error) {
+	query := u.Query()
+
+	parseInt := func(key string, dst *int) error {
+		if !query.Has(key) {
+			return nil
+		}
+		value, err := strconv.Atoi(query.Get(key))
+		if err != nil {
+			return fmt.Errorf("invalid relay option %s: %w", key, err)
+		}
+		*dst = value
+		query.Del(key)
+		return nil
+	}
+
+	if query.Has(relayOptionDisabled) {
+		options.Disabled, err = strconv.ParseBool(query.Get(relayOptionDisabled))
+		if err != nil {
+			return options, fmt.Errorf("invalid relay option %s: %w", relayOptionDisabled, err)
+		}
+		query.Del(relayOptionDisabled)
+	}
+	for key, dst := range map[string]*int{
+		relayOptionRequestTimeoutMs:  &options.RequestTimeoutMs,
+		relayOptionRequestMaxRetries: &options.RequestMaxRetries,
+		relayOptionRetryDelayMs:      &options.RetryDelayMs,
+		relayOptionPriority:          &options.Priority,
+	} {
+		if err := parseInt(key, dst); err != nil {
+			return options, err
+		}
+	}
+	if query.Has(relayOptionMinBid) {
+		options.MinBid = query.Get(relayOptionMinBid)
+		query.Del(relayOptionMinBid)
+	}
+	for _, header := range query[relayOptionHeader] {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			return options, fmt.Errorf("invalid relay option %s %q, expected NAME:VALUE", relayOptionHeader, header)
+		}
+		if options.Headers == nil {
+			options.Headers = make(map[string]string)
+		}
+		options.Headers[name] = value
+	}
+	query.Del(relayOptionHeader)
+
+	if err := options.Validate(); err != nil {
+		return options, err
+	}
+
+	u.RawQuery = query.Encode()
+	return options, nil
+}
+
 // RelayEntriesToStrings returns the string representation of a list of relay entries
 func RelayEntriesToStrings(relays []RelayEntry) []string {
 	ret := make([]string, len(relays))
@@ -68,3 +149,46 @@ func RelayEntriesToStrings(relays []RelayEntry) []string {
 	}
 	return ret
 }
+
+// sameRelay reports whether the entries are the same relay, with the same public key and host
+func (r *RelayEntry) sameRelay(other RelayEntry) bool {
+	return r.PublicKey == other.PublicKey && r.URL.Host == other.URL.Host
+}
+
+// findRelayEntry returns the configured relay entry matching a relay named in the proposer config
+func (r *RelayService) findRelayEntry(relayURL string) (RelayEntry, error) {
+	entry, err := NewRelayEntry(relayURL)
+	if err != nil {
+		return entry, err
+	}
+
+	r.relaysLock.RLock()
+	defer r.relaysLock.RUnlock()
+
+	for _, relay := range r.relays {
+		if relay.sameRelay(entry) {
+			return relay, nil
+		}
+	}
+
+	return entry, fmt.Errorf("%w: %s", ErrUnknownProposerRelay, entry.String())
+}
+
+// relaysForProposer returns the enabled relay entries the proposer config assigns to the proposer,
+// all enabled relay entries if the proposer config does not restrict them
+func (r *RelayService) relaysForProposer(pubkey string) []RelayEntry {
+	relayURLs := r.proposerConfig.For(pubkey).Relays
+	if relayURLs == nil {
+		return r.relayEntries()
+	}
+
+	relays := make([]RelayEntry, 0, len(relayURLs))
+	for _, relayURL := range relayURLs {
+		// Relays in the proposer config are checked against the relay entries on start,
+		// relays removed since are skipped
+		if relay, err := r.findRelayEntry(relayURL); err == nil && !relay.Options.Disabled {
+			relays = append(relays, relay)
+		}
+	}
+	return relays
+}
@@ -0,0 +1,290 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	bellatrixApi "github.com/attestantio/go-builder-client/api/bellatrix"
+	capellaApi "github.com/attestantio/go-builder-client/api/capella"
+	denebApi "github.com/attestantio/go-builder-client/api/deneb"
+	"github.com/attestantio/go-builder-client/spec"
+	apiv1Capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	consensusspec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonTypes "github.com/bsn-eng/pon-golang-types/common"
+	"github.com/holiman/uint256"
+	commonType "github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/modules/relay/config"
+	"github.com/pon-network/mev-plus/modules/relay/signing"
+	"github.com/sirupsen/logrus"
+)
+
+var testParentHash = phase0.Hash32{0xaa}
+
+func testSecretKey(t *testing.T, seed byte) (*signing.SecretKey, phase0.BLSPubKey) {
+	skBytes := make([]byte, signing.SecretKeyLength)
+	skBytes[31] = seed
+	sk, err := signing.SecretKeyFromBytes(skBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubkey phase0.BLSPubKey
+	pkBytes := signing.PublicKeyFromSecretKey(sk).Bytes()
+	copy(pubkey[:], pkBytes[:])
+	return sk, pubkey
+}
+
+// testSignedBid returns a bid of the fork version signed with the secret key under the domain
+func testSignedBid(t *testing.T, version consensusspec.DataVersion, sk *signing.SecretKey, pubkey phase0.BLSPubKey, domain phase0.Domain) *spec.VersionedSignedBuilderBid {
+	value := uint256.NewInt(100)
+	blockHash := phase0.Hash32{0x01}
+
+	bid := &spec.VersionedSignedBuilderBid{Version: version}
+	switch version {
+	case consensusspec.DataVersionBellatrix:
+		bid.Bellatrix = &bellatrixApi.SignedBuilderBid{Message: &bellatrixApi.BuilderBid{
+			Header: &bellatrix.ExecutionPayloadHeader{ParentHash: testParentHash, BlockHash: blockHash},
+			Value:  value,
+			Pubkey: pubkey,
+		}}
+	case consensusspec.DataVersionCapella:
+		bid.Capella = &capellaApi.SignedBuilderBid{Message: &capellaApi.BuilderBid{
+			Header: &capella.ExecutionPayloadHeader{ParentHash: testParentHash, BlockHash: blockHash},
+			Value:  value,
+			Pubkey: pubkey,
+		}}
+	case consensusspec.DataVersionDeneb:
+		bid.Deneb = &denebApi.SignedBuilderBid{Message: &denebApi.BuilderBid{
+			Header:             &deneb.ExecutionPayloadHeader{ParentHash: testParentHash, BlockHash: blockHash, BaseFeePerGas: uint256.NewInt(1)},
+			BlobKZGCommitments: []deneb.KZGCommitment{},
+			Value:              value,
+			Pubkey:             pubkey,
+		}}
+	}
+
+	root, err := bid.MessageHashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := signing.SignRoot(root, domain, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigBytes := sig.Bytes()
+	switch version {
+	case consensusspec.DataVersionBellatrix:
+		copy(bid.Bellatrix.Signature[:], sigBytes[:])
+	case consensusspec.DataVersionCapella:
+		copy(bid.Capella.Signature[:], sigBytes[:])
+	case consensusspec.DataVersionDeneb:
+		copy(bid.Deneb.Signature[:], sigBytes[:])
+	}
+	return bid
+}
+
+func TestConfigureSigningDomain(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+
+	t.Run("ComputesBuilderDomain", func(t *testing.T) {
+		r := NewRelayService()
+		err := r.Configure(commonType.ModuleFlags{
+			config.RelayEntriesFlag.Name: "http://" + pubkey.String() + "@localhost:18550,http://" + pubkey.String() + "@localhost:18551",
+			config.MainnetFlag.Name:      "true",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, err := signing.ComputeDomain(signing.DomainTypeAppBuilder, "0x00000000", config.RelayConfigDefaults.GenesisValidatorsRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, relay := range r.relays {
+			if relay.SigningDomain != phase0.Domain(expected) {
+				t.Errorf("Expected relay %s to get the builder domain, got %x", relay.String(), relay.SigningDomain)
+			}
+		}
+	})
+
+	t.Run("SkipSignatureCheck", func(t *testing.T) {
+		r := NewRelayService()
+		if !r.relaySignatureCheck {
+			t.Fatal("Expected relay signatures to be checked by default")
+		}
+
+		err := r.Configure(commonType.ModuleFlags{
+			config.RelayEntriesFlag.Name:        "http://" + pubkey.String() + "@localhost:18550",
+			config.SkipRelaySignatureCheck.Name: "false",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.relaySignatureCheck {
+			t.Error("Expected relay signatures to be checked when the skip flag is false")
+		}
+
+		r = NewRelayService()
+		err = r.Configure(commonType.ModuleFlags{
+			config.RelayEntriesFlag.Name:        "http://" + pubkey.String() + "@localhost:18550",
+			config.SkipRelaySignatureCheck.Name: "true",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.relaySignatureCheck || r.relays[0].SigningDomain != (phase0.Domain{}) {
+			t.Error("Expected no relay signature check or domain when skipped")
+		}
+	})
+}
+
+func TestRequestRelayHeaderSignature(t *testing.T) {
+	sk, pubkey := testSecretKey(t, 1)
+	otherSk, _ := testSecretKey(t, 2)
+
+	domain, err := signing.ComputeDomain(signing.DomainTypeAppBuilder, "0x00000000", config.RelayConfigDefaults.GenesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions := []consensusspec.DataVersion{consensusspec.DataVersionBellatrix, consensusspec.DataVersionCapella, consensusspec.DataVersionDeneb}
+	for _, version := range versions {
+		t.Run(version.String(), func(t *testing.T) {
+			cases := []struct {
+				name     string
+				bid      *spec.VersionedSignedBuilderBid
+				accepted bool
+				reason   string
+			}{
+				{"SignedByRelay", testSignedBid(t, version, sk, pubkey, phase0.Domain(domain)), true, ""},
+				{"SignedWithOtherKey", testSignedBid(t, version, otherSk, pubkey, phase0.Domain(domain)), false, bidSignatureInvalid},
+				{"SignedWithOtherDomain", testSignedBid(t, version, sk, pubkey, phase0.Domain{}), false, bidSignatureInvalid},
+			}
+
+			for _, c := range cases {
+				t.Run(c.name, func(t *testing.T) {
+					server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+						w.Header().Set("Content-Type", "application/json")
+						if err := json.NewEncoder(w).Encode(c.bid); err != nil {
+							t.Error(err)
+						}
+					}))
+					defer server.Close()
+
+					r := NewRelayService()
+					relay, err := NewRelayEntry(strings.Replace(server.URL, "http://", "http://"+pubkey.String()+"@", 1))
+					if err != nil {
+						t.Fatal(err)
+					}
+					relay.SigningDomain = phase0.Domain(domain)
+
+					var mu sync.Mutex
+					var result bidResp
+					r.requestRelayHeader(1, testParentHash.String(), pubkey.String(), big.NewInt(0), relay, logrus.NewEntry(logrus.New()), &mu, &result, make(map[string][]RelayEntry))
+
+					if accepted := !result.response.IsEmpty(); accepted != c.accepted {
+						t.Fatalf("Expected bid accepted %t, got %t", c.accepted, accepted)
+					}
+					if c.reason != "" {
+						if bidVerificationFailures.Get(bidVerificationFailureKey(relay, c.reason)) == nil {
+							t.Errorf("Expected the %s failure to be counted for the relay", c.reason)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestRecordBidVerificationFailureConcurrent(t *testing.T) {
+	relay, err := NewRelayEntry("http://0x8a1d7b8dd64e0aafe7ea7b6c95065c9364cf99d38470c12ee807d55f7de1529ad29ce2c422e0b65e3d5a05c02caca249@concurrent.relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recordBidVerificationFailure(relay, bidSignatureInvalid)
+		}()
+	}
+	wg.Wait()
+
+	if failures := bidVerificationFailures.Get(bidVerificationFailureKey(relay, bidSignatureInvalid)); failures == nil || failures.String() != "50" {
+		t.Errorf("Expected every concurrent failure to be counted, got %v", failures)
+	}
+}
+
+func TestSubmitBlindedBlock(t *testing.T) {
+	sk, pubkey := testSecretKey(t, 1)
+	block := &commonTypes.VersionedSignedBlindedBeaconBlock{
+		Capella: &apiv1Capella.SignedBlindedBeaconBlock{
+			Message: &apiv1Capella.BlindedBeaconBlock{
+				Slot: 1,
+				Body: &apiv1Capella.BlindedBeaconBlockBody{
+					ExecutionPayloadHeader: &capella.ExecutionPayloadHeader{BlockHash: phase0.Hash32{0x01}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		v2Status int
+		wantErr  error
+		wantV1   bool
+	}{
+		{"Accepted", http.StatusAccepted, nil, false},
+		{"FallsBackToV1", http.StatusNotFound, ErrBlockNotDelivered, true},
+		{"Rejected", http.StatusBadRequest, ErrBlockNotDelivered, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var calledV1 bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				switch req.URL.Path {
+				case pathSubmitBlindedBlockV2:
+					if got := req.Header.Get(HeaderEthConsensusVersion); got != "capella" {
+						t.Errorf("Expected the capella consensus version header, got %q", got)
+					}
+					w.WriteHeader(c.v2Status)
+				case pathGetPayload:
+					// No payload is returned, the block is not delivered through v1 either
+					calledV1 = true
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			defer server.Close()
+
+			r := NewRelayService()
+			r.log.Logger.SetOutput(io.Discard)
+			relay, err := NewRelayEntry(strings.Replace(server.URL, "http://", "http://"+pubkey.String()+"@", 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.bids[bidRespKey{slot: 1, blockHash: phase0.Hash32{0x01}.String()}] = bidResp{
+				response: *testSignedBid(t, consensusspec.DataVersionCapella, sk, pubkey, phase0.Domain{}),
+				relays:   []RelayEntry{relay},
+			}
+
+			if err := r.SubmitBlindedBlock(block); !errors.Is(err, c.wantErr) {
+				t.Fatalf("Expected error %v, got %v", c.wantErr, err)
+			}
+			if calledV1 != c.wantV1 {
+				t.Errorf("Expected v1 fallback %t, got %t", c.wantV1, calledV1)
+			}
+		})
+	}
+}
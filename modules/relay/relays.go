@@ -0,0 +1,251 @@
+package relay
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"time"
+
+	commonType "github.com/pon-network/mev-plus/common"
+	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
+	"github.com/pon-network/mev-plus/modules/relay/config"
+)
+
+// relayEntries returns a copy of the enabled relay entries, safe to use while relays are changed at runtime
+func (r *RelayService) relayEntries() []RelayEntry {
+	r.relaysLock.RLock()
+	defer r.relaysLock.RUnlock()
+
+	relays := make([]RelayEntry, 0, len(r.relays))
+	for _, relay := range r.relays {
+		if !relay.Options.Disabled {
+			relays = append(relays, relay)
+		}
+	}
+	return relays
+}
+
+// relayHTTPClient returns the HTTP client for requests to the relay, with the relay request timeout if set
+func (r *RelayService) relayHTTPClient(relay RelayEntry) http.Client {
+	client := r.httpClient
+	if relay.Options.RequestTimeoutMs > 0 {
+		client.Timeout = time.Duration(relay.Options.RequestTimeoutMs) * time.Millisecond
+	}
+	return client
+}
+
+// relayRetryPolicy returns the number of attempts and the delay between attempts of retried requests to the relay
+func (r *RelayService) relayRetryPolicy(relay RelayEntry) (maxRetries int, retryDelay time.Duration) {
+	maxRetries, retryDelay = r.cfg.RequestMaxRetries, defaultRetryDelay
+	if relay.Options.RequestMaxRetries > 0 {
+		maxRetries = relay.Options.RequestMaxRetries
+	}
+	if relay.Options.RetryDelayMs > 0 {
+		retryDelay = time.Duration(relay.Options.RetryDelayMs) * time.Millisecond
+	}
+	return maxRetries, retryDelay
+}
+
+// minBidFor returns the min bid of bids from the relay. A min bid in the proposer config replaces
+// the relay min bid, which replaces the min bid of the relay module.
+func (r *RelayService) minBidFor(relay RelayEntry, proposerMinBid *big.Int) *big.Int {
+	if proposerMinBid != nil {
+		return proposerMinBid
+	}
+	if minBid, ok := relay.Options.MinBidValue(); ok {
+		return minBid
+	}
+	return r.relayMinBid.BigInt()
+}
+
+// relayIndex returns the index of the relay in the relay list, -1 if it is not in the list.
+// The caller must hold relaysLock.
+func (r *RelayService) relayIndex(entry RelayEntry) int {
+	for i, relay := range r.relays {
+		if relay.sameRelay(entry) {
+			return i
+		}
+	}
+	return -1
+}
+
+// listRelays returns every relay of the relay list with its options, listing only the names of its headers
+func (r *RelayService) listRelays() []relayCommon.RelayInfo {
+	r.relaysLock.RLock()
+	defer r.relaysLock.RUnlock()
+
+	relays := make([]relayCommon.RelayInfo, 0, len(r.relays))
+	for _, relay := range r.relays {
+		info := relayCommon.RelayInfo{
+			URL:       relay.String(),
+			PublicKey: relay.PublicKey.String(),
+			Options:   relay.Options,
+		}
+		info.Options.Headers = nil
+		for name := range relay.Options.Headers {
+			info.HeaderNames = append(info.HeaderNames, name)
+		}
+		sort.Strings(info.HeaderNames)
+		relays = append(relays, info)
+	}
+	return relays
+}
+
+// addRelay adds the relay to the relay list, signing domain set, and saves the relay config file.
+// The relay list is only changed once the file is saved.
+func (r *RelayService) addRelay(entry RelayEntry) error {
+	r.relaysLock.Lock()
+	defer r.relaysLock.Unlock()
+
+	if r.relayIndex(entry) != -1 {
+		return fmt.Errorf("%w: %s", ErrDuplicateRelay, entry.String())
+	}
+	if err := entry.Options.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRelayOptions, err)
+	}
+
+	if r.relaySignatureCheck {
+		entry.SigningDomain = r.signingDomain
+	}
+	relays := make([]RelayEntry, 0, len(r.relays)+1)
+	relays = append(append(relays, r.relays...), entry)
+	if err := r.saveRelayFile(relays); err != nil {
+		return err
+	}
+	r.relays = relays
+
+	return nil
+}
+
+// removeRelay removes the relay from the relay list and from the bids it delivered,
+// dropping bids no remaining relay delivered, and saves the relay config file.
+// The relay list is only changed once the file is saved.
+func (r *RelayService) removeRelay(entry RelayEntry) error {
+	r.relaysLock.Lock()
+	defer r.relaysLock.Unlock()
+
+	i := r.relayIndex(entry)
+	if i == -1 {
+		return fmt.Errorf("%w: %s", ErrUnknownRelay, entry.String())
+	}
+	removed := r.relays[i]
+	relays := append(r.relays[:i:i], r.relays[i+1:]...)
+	if err := r.saveRelayFile(relays); err != nil {
+		return err
+	}
+	r.relays = relays
+
+	r.bidsLock.Lock()
+	for key, bid := range r.bids {
+		relays := make([]RelayEntry, 0, len(bid.relays))
+		for _, relay := range bid.relays {
+			if !relay.sameRelay(removed) {
+				relays = append(relays, relay)
+			}
+		}
+		if len(relays) == 0 {
+			delete(r.bids, key)
+			continue
+		}
+		bid.relays = relays
+		r.bids[key] = bid
+	}
+	r.bidsLock.Unlock()
+
+	r.health.remove(removed)
+
+	r.log.WithField("relay", removed.String()).Info("Removed relay")
+
+	return nil
+}
+
+// setRelayOptions replaces the options of the relay, including in the bids it delivered, and saves the relay config file.
+// The relay options are only changed once the file is saved.
+func (r *RelayService) setRelayOptions(entry RelayEntry, options relayCommon.RelayOptions) error {
+	r.relaysLock.Lock()
+	defer r.relaysLock.Unlock()
+
+	if err := options.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRelayOptions, err)
+	}
+
+	i := r.relayIndex(entry)
+	if i == -1 {
+		return fmt.Errorf("%w: %s", ErrUnknownRelay, entry.String())
+	}
+	relays := make([]RelayEntry, len(r.relays))
+	copy(relays, r.relays)
+	relays[i].Options = options
+	if err := r.saveRelayFile(relays); err != nil {
+		return err
+	}
+	r.relays = relays
+
+	r.bidsLock.Lock()
+	for key, bid := range r.bids {
+		for j := range bid.relays {
+			if bid.relays[j].sameRelay(entry) {
+				bid.relays[j].Options = options
+			}
+		}
+		r.bids[key] = bid
+	}
+	r.bidsLock.Unlock()
+
+	return nil
+}
+
+// saveRelayFile writes the relay list to the relay config file, if one is used.
+// The caller must hold relaysLock.
+func (r *RelayService) saveRelayFile(relays []RelayEntry) error {
+	if r.relayConfigPath == "" {
+		return nil
+	}
+
+	file := config.RelayFile{Relays: make([]config.RelayFileEntry, 0, len(relays))}
+	for _, relay := range relays {
+		file.Relays = append(file.Relays, config.RelayFileEntry{URL: relay.String(), Options: relay.Options})
+	}
+	if err := file.Save(r.relayConfigPath); err != nil {
+		return fmt.Errorf("failed to save relay config %s: %w", r.relayConfigPath, err)
+	}
+	return nil
+}
+
+// loadRelayFile adds the relays of the relay config file to the relay list, the file options
+// replacing those of relays already set by the relay entries flag
+func (r *RelayService) loadRelayFile(path string) error {
+	file, err := config.LoadRelayFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, fileEntry := range file.Relays {
+		entry, err := NewRelayEntry(fileEntry.URL)
+		if err != nil {
+			return fmt.Errorf("invalid relay %s in relay config %s: %w", fileEntry.URL, path, err)
+		}
+		if err := fileEntry.Options.Validate(); err != nil {
+			return fmt.Errorf("invalid options of relay %s in relay config %s: %w", fileEntry.URL, path, err)
+		}
+		entry.Options = fileEntry.Options
+		if i := r.relayIndex(entry); i != -1 {
+			r.relays[i].Options = entry.Options
+			continue
+		}
+		r.relays = append(r.relays, entry)
+	}
+
+	r.relayConfigPath = path
+	return nil
+}
+
+// parseRelayCall parses the relay URL of a relay management call, as an invalid params error
+func parseRelayCall(relayURL string) (RelayEntry, error) {
+	entry, err := NewRelayEntry(relayURL)
+	if err != nil {
+		return entry, &commonType.InvalidParamsError{Message: err.Error()}
+	}
+	return entry, nil
+}
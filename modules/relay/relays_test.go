@@ -0,0 +1,302 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/go-builder-client/spec"
+	consensusspec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	commonType "github.com/pon-network/mev-plus/common"
+	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
+	"github.com/pon-network/mev-plus/modules/relay/config"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRelayManagement(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+	_, otherPubkey := testSecretKey(t, 2)
+	first := "http://" + pubkey.String() + "@localhost:18550"
+	second := "http://" + otherPubkey.String() + "@localhost:18551"
+
+	path := filepath.Join(t.TempDir(), "relays.json")
+	file := config.RelayFile{Relays: []config.RelayFileEntry{{URL: first}}}
+	if err := file.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRelayService()
+	err := r.Configure(commonType.ModuleFlags{
+		config.RelayEntriesFlag.Name: first,
+		config.RelayConfigFlag.Name:  path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relays := r.listRelays(); len(relays) != 1 {
+		t.Fatalf("Expected the relay of both the flag and the file once, got %v", relays)
+	}
+
+	firstEntry, _ := NewRelayEntry(first)
+	secondEntry, _ := NewRelayEntry(second)
+	if err := r.addRelay(secondEntry); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.addRelay(secondEntry); !errors.Is(err, ErrDuplicateRelay) {
+		t.Errorf("Expected a duplicate relay to be refused, got %v", err)
+	}
+	if r.relays[1].SigningDomain != r.signingDomain {
+		t.Error("Expected the added relay to get the builder domain")
+	}
+
+	// A bid delivered by both relays
+	key := bidRespKey{slot: 1, blockHash: "0x01"}
+	r.bids[key] = bidResp{relays: []RelayEntry{firstEntry, secondEntry}}
+
+	if err := r.setRelayOptions(firstEntry, relayCommon.RelayOptions{Disabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if relays := r.relayEntries(); len(relays) != 1 || !relays[0].sameRelay(secondEntry) {
+		t.Errorf("Expected only the enabled relay to be used, got %v", RelayEntriesToStrings(relays))
+	}
+	if !r.bids[key].relays[0].Options.Disabled {
+		t.Error("Expected the relay options to be updated in the bids")
+	}
+
+	saved, err := config.LoadRelayFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Relays) != 2 || !saved.Relays[0].Options.Disabled {
+		t.Errorf("Expected the relay changes to be saved, got %+v", saved.Relays)
+	}
+
+	if err := r.removeRelay(firstEntry); err != nil {
+		t.Fatal(err)
+	}
+	if bid := r.bids[key]; len(bid.relays) != 1 || !bid.relays[0].sameRelay(secondEntry) {
+		t.Errorf("Expected the removed relay to be dropped from the bid, got %v", RelayEntriesToStrings(bid.relays))
+	}
+	if err := r.removeRelay(secondEntry); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.bids[key]; ok {
+		t.Error("Expected the bid to be dropped once no relay delivered it")
+	}
+	if err := r.removeRelay(secondEntry); !errors.Is(err, ErrUnknownRelay) {
+		t.Errorf("Expected an unknown relay to be refused, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := config.LoadRelayFile(path); len(saved.Relays) != 0 {
+		t.Errorf("Expected an empty relay list to be saved, got %s", data)
+	}
+}
+
+func TestRelayManagementSaveFailure(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+	_, otherPubkey := testSecretKey(t, 2)
+	entry, _ := NewRelayEntry("http://" + pubkey.String() + "@localhost:18550")
+	otherEntry, _ := NewRelayEntry("http://" + otherPubkey.String() + "@localhost:18551")
+
+	r := NewRelayService()
+	if err := r.addRelay(entry); err != nil {
+		t.Fatal(err)
+	}
+	key := bidRespKey{slot: 1, blockHash: "0x01"}
+	r.bids[key] = bidResp{relays: []RelayEntry{entry}}
+
+	// The relay config file can not be written, in a directory that does not exist
+	r.relayConfigPath = filepath.Join(t.TempDir(), "missing", "relays.json")
+
+	if err := r.addRelay(otherEntry); err == nil {
+		t.Error("Expected adding a relay to fail")
+	}
+	if err := r.setRelayOptions(entry, relayCommon.RelayOptions{Disabled: true}); err == nil {
+		t.Error("Expected setting the relay options to fail")
+	}
+	if err := r.removeRelay(entry); err == nil {
+		t.Error("Expected removing a relay to fail")
+	}
+
+	if relays := r.listRelays(); len(relays) != 1 || relays[0].URL != entry.String() || relays[0].Options.Disabled {
+		t.Errorf("Expected the relay list to be unchanged, got %+v", relays)
+	}
+	if bid := r.bids[key]; len(bid.relays) != 1 || bid.relays[0].Options.Disabled {
+		t.Errorf("Expected the bids to be unchanged, got %+v", bid)
+	}
+}
+
+func TestListRelaysRedactsHeaders(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+	entry, err := NewRelayEntry("http://" + pubkey.String() + "@localhost:18550?header=X-Api-Key:secret&header=Authorization:Bearer%20token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRelayService()
+	if err := r.addRelay(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	relays := r.listRelays()
+	if len(relays) != 1 {
+		t.Fatalf("Expected one relay, got %d", len(relays))
+	}
+	if relays[0].Options.Headers != nil || !reflect.DeepEqual(relays[0].HeaderNames, []string{"Authorization", "X-Api-Key"}) {
+		t.Errorf("Expected only the header names to be listed, got %+v", relays[0])
+	}
+	data, _ := json.Marshal(relays)
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "token") {
+		t.Errorf("Expected the header values not to be listed, got %s", data)
+	}
+	if len(r.relays[0].Options.Headers) != 2 {
+		t.Error("Expected the headers of the relay to be kept")
+	}
+}
+
+func TestRelayManagementConcurrency(t *testing.T) {
+	r := NewRelayService()
+
+	var wg sync.WaitGroup
+	for i := byte(1); i <= 8; i++ {
+		_, pubkey := testSecretKey(t, i)
+		entry, err := NewRelayEntry("http://" + pubkey.String() + "@localhost:18550")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := r.addRelay(entry); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := r.setRelayOptions(entry, relayCommon.RelayOptions{Disabled: true}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_ = r.relaysForProposer(pubkey.String())
+			_ = r.listRelays()
+		}()
+	}
+	wg.Wait()
+
+	if relays := r.listRelays(); len(relays) != 8 {
+		t.Errorf("Expected 8 relays, got %d", len(relays))
+	}
+	if relays := r.relayEntries(); len(relays) != 0 {
+		t.Errorf("Expected every relay to be disabled, got %d enabled", len(relays))
+	}
+}
+
+func TestParseRelayOptions(t *testing.T) {
+	_, pubkey := testSecretKey(t, 1)
+	base := "https://" + pubkey.String() + "@relay.example.org"
+
+	entry, err := NewRelayEntry(base + "?request_timeout_ms=2000&request_max_retries=5&retry_delay_ms=50&min_bid=1000&priority=2&header=X-Api-Key:secret&disabled=true&id=7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := relayCommon.RelayOptions{
+		Disabled:          true,
+		RequestTimeoutMs:  2000,
+		RequestMaxRetries: 5,
+		RetryDelayMs:      50,
+		MinBid:            "1000",
+		Headers:           map[string]string{"X-Api-Key": "secret"},
+		Priority:          2,
+	}
+	if !reflect.DeepEqual(entry.Options, expected) {
+		t.Errorf("Expected options %+v, got %+v", expected, entry.Options)
+	}
+	if got := entry.GetURI(pathStatus); got != "https://relay.example.org"+pathStatus+"?id=7" {
+		t.Errorf("Expected the options to be removed from the relay URL, got %s", got)
+	}
+
+	for _, query := range []string{"?request_timeout_ms=-1", "?priority=high", "?min_bid=0x10", "?header=X-Api-Key", "?disabled=maybe"} {
+		if _, err := NewRelayEntry(base + query); err == nil {
+			t.Errorf("Expected an error for relay options %s", query)
+		}
+	}
+}
+
+func TestRequestRelayHeaderOptions(t *testing.T) {
+	sk, pubkey := testSecretKey(t, 1)
+
+	newRelay := func(t *testing.T, query string, bid *spec.VersionedSignedBuilderBid) RelayEntry {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("X-Api-Key") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(bid); err != nil {
+				t.Error(err)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		relay, err := NewRelayEntry(strings.Replace(server.URL, "http://", "http://"+pubkey.String()+"@", 1) + "?header=X-Api-Key:secret" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return relay
+	}
+
+	request := func(r *RelayService, relays ...RelayEntry) bidResp {
+		var mu sync.Mutex
+		var result bidResp
+		for _, relay := range relays {
+			r.requestRelayHeader(1, testParentHash.String(), pubkey.String(), r.minBidFor(relay, nil), relay, logrus.NewEntry(logrus.New()), &mu, &result, make(map[string][]RelayEntry))
+		}
+		return result
+	}
+
+	r := NewRelayService()
+	r.relaySignatureCheck = false
+	bid := testSignedBid(t, consensusspec.DataVersionCapella, sk, pubkey, phase0.Domain{})
+
+	t.Run("Headers", func(t *testing.T) {
+		if result := request(r, newRelay(t, "", bid)); result.response.IsEmpty() {
+			t.Error("Expected the bid of the relay requiring the API key header")
+		}
+	})
+
+	t.Run("MinBid", func(t *testing.T) {
+		if result := request(r, newRelay(t, "&min_bid=101", bid)); !result.response.IsEmpty() {
+			t.Error("Expected the bid below the relay min bid to be ignored")
+		}
+		if result := request(r, newRelay(t, "&min_bid=100", bid)); result.response.IsEmpty() {
+			t.Error("Expected the bid at the relay min bid to be used")
+		}
+	})
+
+	t.Run("Priority", func(t *testing.T) {
+		// Equal value bids, the lower block hash would win without priorities
+		otherBid := testSignedBid(t, consensusspec.DataVersionCapella, sk, pubkey, phase0.Domain{})
+		otherBid.Capella.Message.Header.BlockHash = phase0.Hash32{0x02}
+
+		low, high := newRelay(t, "", bid), newRelay(t, "&priority=1", otherBid)
+		for _, order := range [][]RelayEntry{{low, high}, {high, low}} {
+			result := request(r, order...)
+			if result.bidInfo.blockHash != (phase0.Hash32{0x02}) {
+				t.Errorf("Expected the bid of the relay with the higher priority, got %s", result.bidInfo.blockHash.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+type (
+	PublicKey = bls12381.G1Affine
+	SecretKey = fr.Element
+	Signature = bls12381.G2Affine
+)
+
+const (
+	PublicKeyLength = bls12381.SizeOfG1AffineCompressed
+	SecretKeyLength = fr.Bytes
+	SignatureLength = bls12381.SizeOfG2AffineCompressed
+)
+
+var (
+	_, _, g1Aff, _            = bls12381.Generators()
+	dst                       = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+	ErrInvalidPubkeyLength    = errors.New("invalid public key length")
+	ErrInvalidSecretKeyLength = errors.New("invalid secret key length")
+	ErrInvalidSignatureLength = errors.New("invalid signature length")
+	ErrSecretKeyIsZero        = errors.New("invalid secret key is zero")
+	ErrInfinitePubkey         = errors.New("invalid public key is the point at infinity")
+	ErrInfiniteSignature      = errors.New("invalid signature is the point at infinity")
+	ErrPubkeyNotInSubgroup    = errors.New("invalid public key is not in the G1 subgroup")
+	ErrSignatureNotInSubgroup = errors.New("invalid signature is not in the G2 subgroup")
+)
+
+// ComputeSigningRoot returns the root that is signed for an object root under the given domain
+func ComputeSigningRoot(objectRoot [32]byte, domain phase0.Domain) ([32]byte, error) {
+	signingData := phase0.SigningData{ObjectRoot: objectRoot, Domain: domain}
+	return signingData.HashTreeRoot()
+}
+
+// VerifySignedRoot verifies a BLS signature over the signing root of an object root and domain
+func VerifySignedRoot(objectRoot [32]byte, domain phase0.Domain, sigBytes, pkBytes []byte) (bool, error) {
+	msg, err := ComputeSigningRoot(objectRoot, domain)
+	if err != nil {
+		return false, err
+	}
+	return VerifySignatureBytes(msg[:], sigBytes, pkBytes)
+}
+
+func VerifySignatureBytes(msg, sigBytes, pkBytes []byte) (bool, error) {
+	pk, err := PublicKeyFromBytes(pkBytes)
+	if err != nil {
+		return false, err
+	}
+	sig, err := SignatureFromBytes(sigBytes)
+	if err != nil {
+		return false, err
+	}
+	return VerifySignature(sig, pk, msg)
+}
+
+// SecretKeyFromBytes returns the secret key of big endian bytes, which must be below the curve order and not zero
+func SecretKeyFromBytes(skBytes []byte) (*SecretKey, error) {
+	if len(skBytes) != SecretKeyLength {
+		return nil, ErrInvalidSecretKeyLength
+	}
+	sk := new(SecretKey)
+	if err := sk.SetBytesCanonical(skBytes); err != nil {
+		return nil, err
+	}
+	if sk.IsZero() {
+		return nil, ErrSecretKeyIsZero
+	}
+	return sk, nil
+}
+
+// PublicKeyFromSecretKey returns the public key of the secret key
+func PublicKeyFromSecretKey(sk *SecretKey) *PublicKey {
+	return new(PublicKey).ScalarMultiplication(&g1Aff, sk.BigInt(new(big.Int)))
+}
+
+// Sign returns the BLS signature of the secret key over the message
+func Sign(sk *SecretKey, msg []byte) (*Signature, error) {
+	Q, err := bls12381.HashToG2(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return new(Signature).ScalarMultiplication(&Q, sk.BigInt(new(big.Int))), nil
+}
+
+// SignRoot signs the signing root of an object root and domain
+func SignRoot(objectRoot [32]byte, domain phase0.Domain, sk *SecretKey) (*Signature, error) {
+	msg, err := ComputeSigningRoot(objectRoot, domain)
+	if err != nil {
+		return nil, err
+	}
+	return Sign(sk, msg[:])
+}
+
+func PublicKeyFromBytes(pkBytes []byte) (*PublicKey, error) {
+	if len(pkBytes) != PublicKeyLength {
+		return nil, ErrInvalidPubkeyLength
+	}
+	pk := new(PublicKey)
+	err := pk.Unmarshal(pkBytes)
+	return pk, err
+}
+
+func SignatureFromBytes(sigBytes []byte) (*Signature, error) {
+	if len(sigBytes) != SignatureLength {
+		return nil, ErrInvalidSignatureLength
+	}
+	sig := new(Signature)
+	err := sig.Unmarshal(sigBytes)
+	return sig, err
+}
+
+// VerifySignature verifies the BLS signature of the public key over the message. Points at infinity and points
+// outside the prime order subgroups are refused before pairing, an infinite key and signature would verify any message.
+func VerifySignature(sig *Signature, pk *PublicKey, msg []byte) (bool, error) {
+	if pk.IsInfinity() {
+		return false, ErrInfinitePubkey
+	}
+	if !pk.IsOnCurve() || !pk.IsInSubGroup() {
+		return false, ErrPubkeyNotInSubgroup
+	}
+	if sig.IsInfinity() {
+		return false, ErrInfiniteSignature
+	}
+	if !sig.IsOnCurve() || !sig.IsInSubGroup() {
+		return false, ErrSignatureNotInSubgroup
+	}
+
+	Q, err := bls12381.HashToG2(msg, dst)
+	if err != nil {
+		return false, err
+	}
+	var negP bls12381.G1Affine
+	negP.Neg(&g1Aff)
+	return bls12381.PairingCheck(
+		[]bls12381.G1Affine{*pk, negP},
+		[]bls12381.G2Affine{Q, *sig},
+	)
+}
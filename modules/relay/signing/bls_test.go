@@ -0,0 +1,155 @@
+package signing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The application builder domain of mainnet, as used by relays and mev-boost
+const mainnetBuilderDomain = "0x00000001f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9"
+
+func TestComputeDomain(t *testing.T) {
+	domain, err := ComputeDomain(DomainTypeAppBuilder, "0x00000000", "0x0000000000000000000000000000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexutil.Encode(domain[:]); got != mainnetBuilderDomain {
+		t.Errorf("Expected the mainnet builder domain %s, got %s", mainnetBuilderDomain, got)
+	}
+
+	if _, err := ComputeDomain(DomainTypeAppBuilder, "0x00000000", "0x00000000000000000000000000000000"); err == nil {
+		t.Error("Expected an error for a genesis validators root that is not 32 bytes")
+	}
+	if _, err := ComputeDomain(DomainTypeAppBuilder, "0x000000", "0x0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
+		t.Error("Expected an error for a fork version that is not 4 bytes")
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	skBytes := make([]byte, SecretKeyLength)
+	skBytes[31] = 42
+	sk, err := SecretKeyFromBytes(skBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := PublicKeyFromSecretKey(sk)
+	pkBytes := pk.Bytes()
+
+	var domain phase0.Domain
+	copy(domain[:], hexutil.MustDecode(mainnetBuilderDomain))
+	root := [32]byte{1, 2, 3}
+
+	sig, err := SignRoot(root, domain, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigBytes := sig.Bytes()
+
+	if ok, err := VerifySignedRoot(root, domain, sigBytes[:], pkBytes[:]); err != nil || !ok {
+		t.Fatalf("Expected the signature to verify, got %t, %v", ok, err)
+	}
+	if ok, _ := VerifySignedRoot(root, phase0.Domain{}, sigBytes[:], pkBytes[:]); ok {
+		t.Error("Expected the signature not to verify under another domain")
+	}
+	if ok, _ := VerifySignedRoot([32]byte{4}, domain, sigBytes[:], pkBytes[:]); ok {
+		t.Error("Expected the signature not to verify for another root")
+	}
+
+	if _, err := SecretKeyFromBytes(make([]byte, SecretKeyLength)); err != ErrSecretKeyIsZero {
+		t.Errorf("Expected a zero secret key to be refused, got %v", err)
+	}
+}
+
+// pointOutsideG1 returns a point of the curve that is not in the prime order subgroup
+func pointOutsideG1(t *testing.T) *PublicKey {
+	var b fp.Element
+	b.SetUint64(4)
+	for i := uint64(1); i < 100; i++ {
+		var x, y, rhs fp.Element
+		x.SetUint64(i)
+		rhs.Square(&x).Mul(&rhs, &x).Add(&rhs, &b)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		y.Sqrt(&rhs)
+		pk := &PublicKey{X: x, Y: y}
+		if pk.IsOnCurve() && !pk.IsInSubGroup() {
+			return pk
+		}
+	}
+	t.Fatal("no point outside the G1 subgroup found")
+	return nil
+}
+
+// pointOutsideG2 returns a point of the twist that is not in the prime order subgroup
+func pointOutsideG2(t *testing.T) *Signature {
+	var b bls12381.E2
+	b.A0.SetUint64(4)
+	b.A1.SetUint64(4)
+	for i := uint64(1); i < 100; i++ {
+		var x, y, rhs bls12381.E2
+		x.A0.SetUint64(i)
+		x.A1.SetOne()
+		rhs.Square(&x).Mul(&rhs, &x).Add(&rhs, &b)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		y.Sqrt(&rhs)
+		sig := &Signature{X: x, Y: y}
+		if sig.IsOnCurve() && !sig.IsInSubGroup() {
+			return sig
+		}
+	}
+	t.Fatal("no point outside the G2 subgroup found")
+	return nil
+}
+
+func TestVerifyRefusesInvalidPoints(t *testing.T) {
+	skBytes := make([]byte, SecretKeyLength)
+	skBytes[31] = 42
+	sk, err := SecretKeyFromBytes(skBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := PublicKeyFromSecretKey(sk)
+	msg := []byte("message")
+	sig, err := Sign(sk, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifySignature(sig, pk, msg); err != nil || !ok {
+		t.Fatalf("Expected the signature to verify, got %t, %v", ok, err)
+	}
+
+	t.Run("Infinity", func(t *testing.T) {
+		// The pairing of an infinite key and signature holds for any message
+		if ok, err := VerifySignature(new(Signature), new(PublicKey), msg); ok || !errors.Is(err, ErrInfinitePubkey) {
+			t.Errorf("Expected an infinite public key to be refused, got %t, %v", ok, err)
+		}
+		if ok, err := VerifySignature(new(Signature), pk, msg); ok || !errors.Is(err, ErrInfiniteSignature) {
+			t.Errorf("Expected an infinite signature to be refused, got %t, %v", ok, err)
+		}
+
+		pkBytes := make([]byte, PublicKeyLength)
+		pkBytes[0] = 0xc0
+		sigBytes := make([]byte, SignatureLength)
+		sigBytes[0] = 0xc0
+		if ok, err := VerifySignatureBytes(msg, sigBytes, pkBytes); ok || err == nil {
+			t.Errorf("Expected encoded points at infinity to be refused, got %t, %v", ok, err)
+		}
+	})
+
+	t.Run("Subgroup", func(t *testing.T) {
+		if ok, err := VerifySignature(sig, pointOutsideG1(t), msg); ok || !errors.Is(err, ErrPubkeyNotInSubgroup) {
+			t.Errorf("Expected a public key outside the subgroup to be refused, got %t, %v", ok, err)
+		}
+		if ok, err := VerifySignature(pointOutsideG2(t), pk, msg); ok || !errors.Is(err, ErrSignatureNotInSubgroup) {
+			t.Errorf("Expected a signature outside the subgroup to be refused, got %t, %v", ok, err)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package signing
 
 import (
 	"errors"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
 )
@@ -26,8 +25,15 @@ type ForkData struct {
 	GenesisValidatorsRoot Root        `ssz-size:"32"`
 }
 
+// ComputeDomain returns the domain of the domain type for the fork version and genesis validators root.
+// The application builder domain uses the genesis fork version and a zero genesis validators root.
 func ComputeDomain(domainType DomainType, forkVersionHex, genesisValidatorsRootHex string) (domain Domain, err error) {
-	genesisValidatorsRoot := Root(common.HexToHash(genesisValidatorsRootHex))
+	genesisValidatorsRootBytes, err := hexutil.Decode(genesisValidatorsRootHex)
+	if err != nil || len(genesisValidatorsRootBytes) != len(Root{}) {
+		return domain, errors.New("Wrong Genesis Validators Root")
+	}
+	var genesisValidatorsRoot Root
+	copy(genesisValidatorsRoot[:], genesisValidatorsRootBytes)
 	forkVersionBytes, err := hexutil.Decode(forkVersionHex)
 	if err != nil || len(forkVersionBytes) != 4 {
 		return domain, errors.New("Wrong Fork Version")
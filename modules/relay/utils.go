@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -16,14 +15,14 @@ import (
 
 	commonTypes "github.com/bsn-eng/pon-golang-types/common"
 	commonType "github.com/pon-network/mev-plus/common"
+	"github.com/pon-network/mev-plus/common/network"
 	relayCommon "github.com/pon-network/mev-plus/modules/relay/common"
 	"github.com/pon-network/mev-plus/modules/relay/config"
+	"github.com/pon-network/mev-plus/modules/relay/signing"
 
 	"github.com/attestantio/go-builder-client/spec"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
-	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
-	"github.com/crate-crypto/go-ipa/bandersnatch/fr"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/holiman/uint256"
 	"github.com/sirupsen/logrus"
@@ -35,6 +34,7 @@ type bidResp struct {
 	response spec.VersionedSignedBuilderBid
 	bidInfo  bidInfo
 	relays   []RelayEntry
+	priority int // priority of the relay the bid was selected from
 }
 
 // bidRespKey is used as key for the bids cache
@@ -43,27 +43,6 @@ type bidRespKey struct {
 	blockHash string
 }
 
-type (
-	PublicKey = bls12381.G1Affine
-	SecretKey = fr.Element
-	Signature = bls12381.G2Affine
-)
-
-const (
-	PublicKeyLength = bls12381.SizeOfG1AffineCompressed
-	SecretKeyLength = fr.Bytes
-	SignatureLength = bls12381.SizeOfG2AffineCompressed
-)
-
-var (
-	_, _, g1Aff, _            = bls12381.Generators()
-	domain                    = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
-	ErrInvalidPubkeyLength    = errors.New("invalid public key length")
-	ErrInvalidSecretKeyLength = errors.New("invalid secret key length")
-	ErrInvalidSignatureLength = errors.New("invalid signature length")
-	ErrSecretKeyIsZero        = errors.New("invalid secret key is zero")
-)
-
 // bidInfo is used to store bid response fields for logging and validation
 type bidInfo struct {
 	blockHash  phase0.Hash32
@@ -129,8 +108,8 @@ func DecodeJSON(r io.Reader, dst any) error {
 	return decoder.Decode(dst)
 }
 
-// SendHTTPRequest - prepare and send HTTP request, marshaling the payload if any, and decoding the response if dst is set
-func SendHTTPRequest(ctx context.Context, client http.Client, method, url string, payload, dst any) (code int, err error) {
+// SendHTTPRequest - prepare and send HTTP request with the headers, marshaling the payload if any, and decoding the response if dst is set
+func SendHTTPRequest(ctx context.Context, client http.Client, method, url string, headers map[string]string, payload, dst any) (code int, err error) {
 	var req *http.Request
 
 	if payload == nil {
@@ -146,6 +125,17 @@ func SendHTTPRequest(ctx context.Context, client http.Client, method, url string
 		return 0, fmt.Errorf("could not prepare request: %w", err)
 	}
 
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	// Versioned requests, such as blinded blocks, name their fork in the consensus version header
+	if versioned, ok := payload.(interface{ Version() (string, error) }); ok {
+		if version, err := versioned.Version(); err == nil {
+			req.Header.Set(HeaderEthConsensusVersion, version)
+		}
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return 0, err
@@ -187,17 +177,12 @@ func checkRelaySignature(bid *spec.VersionedSignedBuilderBid, domain phase0.Doma
 	if err != nil {
 		return false, err
 	}
-	signingData := phase0.SigningData{ObjectRoot: root, Domain: domain}
-	msg, err := signingData.HashTreeRoot()
-	if err != nil {
-		return false, err
-	}
 
-	return VerifySignatureBytes(msg[:], sig[:], pubKey[:])
+	return signing.VerifySignedRoot(root, domain, sig[:], pubKey[:])
 }
 
-// SendHTTPRequestWithRetries - prepare and send HTTP request, retrying the request if within the client timeout
-func SendHTTPRequestWithRetries(ctx context.Context, client http.Client, method, url string, payload, dst any, maxRetries int, log *logrus.Entry) (code int, err error) {
+// SendHTTPRequestWithRetries - prepare and send HTTP request, retrying the request after the retry delay if within the client timeout
+func SendHTTPRequestWithRetries(ctx context.Context, client http.Client, method, url string, headers map[string]string, payload, dst any, maxRetries int, retryDelay time.Duration, log *logrus.Entry) (code int, err error) {
 	// Create a context with a timeout as configured in the HTTP client
 	requestCtx, cancel := context.WithTimeout(ctx, client.Timeout)
 	defer cancel()
@@ -207,13 +192,13 @@ func SendHTTPRequestWithRetries(ctx context.Context, client http.Client, method,
 			return 0, fmt.Errorf("request context error after %d attempts: %w", attempts, requestCtx.Err())
 		}
 
-		code, err = SendHTTPRequest(ctx, client, method, url, payload, dst)
+		code, err = SendHTTPRequest(ctx, client, method, url, headers, payload, dst)
 		if err == nil {
 			return code, nil
 		}
 
 		log.WithError(err).Warn("Error making request to relay, retrying")
-		time.Sleep(100 * time.Millisecond)
+		time.Sleep(retryDelay)
 	}
 
 	return 0, ErrMaxRetriesExceeded
@@ -223,55 +208,32 @@ func httpClientDisallowRedirects(_ *http.Request, _ []*http.Request) error {
 	return http.ErrUseLastResponse
 }
 
-func VerifySignatureBytes(msg, sigBytes, pkBytes []byte) (bool, error) {
-	pk, err := PublicKeyFromBytes(pkBytes)
-	if err != nil {
-		return false, err
-	}
-	sig, err := SignatureFromBytes(sigBytes)
-	if err != nil {
-		return false, err
-	}
-	return VerifySignature(sig, pk, msg)
-}
-
-func PublicKeyFromBytes(pkBytes []byte) (*PublicKey, error) {
-	if len(pkBytes) != PublicKeyLength {
-		return nil, ErrInvalidPubkeyLength
-	}
-	pk := new(PublicKey)
-	err := pk.Unmarshal(pkBytes)
-	return pk, err
-}
-
-func SignatureFromBytes(sigBytes []byte) (*Signature, error) {
-	if len(sigBytes) != SignatureLength {
-		return nil, ErrInvalidSignatureLength
-	}
-	sig := new(Signature)
-	err := sig.Unmarshal(sigBytes)
-	return sig, err
-}
-
-func VerifySignature(sig *Signature, pk *PublicKey, msg []byte) (bool, error) {
-	Q, err := bls12381.HashToG2(msg, domain)
-	if err != nil {
-		return false, err
-	}
-	var negP bls12381.G1Affine
-	negP.Neg(&g1Aff)
-	return bls12381.PairingCheck(
-		[]bls12381.G1Affine{*pk, negP},
-		[]bls12381.G2Affine{Q, *sig},
-	)
-}
-
 func ParseConfigFLags(r *RelayService, moduleFlags commonType.ModuleFlags) error {
 
 	var forkVersionFlagNameSet string
 	var customGenesisTime bool
 	var customForkVersion bool
 
+	setNetwork := func(flagName, name string) error {
+		if forkVersionFlagNameSet != "" || customForkVersion {
+			return fmt.Errorf("cannot set %s and %s", flagName, forkVersionFlagNameSet)
+		}
+		n, err := network.Load(name)
+		if err != nil {
+			return err
+		}
+		forkVersionFlagNameSet = flagName
+		r.cfg.GenesisForkVersion = n.GenesisForkVersion
+		return nil
+	}
+
+	// The network is applied first so custom genesis flags can be checked against it
+	if name, ok := moduleFlags[config.NetworkFlag.Name]; ok {
+		if err := setNetwork(config.NetworkFlag.Name, name); err != nil {
+			return err
+		}
+	}
+
 	for flagName, flagValue := range moduleFlags {
 		switch flagName {
 		case config.LoggerLevelFlag.Name:
@@ -298,10 +260,16 @@ func ParseConfigFLags(r *RelayService, moduleFlags commonType.ModuleFlags) error
 				}
 				r.relays = append(r.relays, relayEntry)
 			}
+		case config.RelayConfigFlag.Name:
+			// Applied after the relay entries
 		case config.RelayCheckFlag.Name:
 			r.relayCheck = true
 		case config.SkipRelaySignatureCheck.Name:
-			r.relaySignatureCheck = false
+			skip, err := strconv.ParseBool(flagValue)
+			if err != nil {
+				return err
+			}
+			r.relaySignatureCheck = !skip
 		case config.MinBidFlag.Name:
 			minBidBigInt := new(big.Int)
 			minBidBigInt, ok := minBidBigInt.SetString(flagValue, 10)
@@ -315,26 +283,19 @@ func ParseConfigFLags(r *RelayService, moduleFlags commonType.ModuleFlags) error
 			}
 			r.relayMinBid = minBid
 		case config.MainnetFlag.Name:
-			if forkVersionFlagNameSet != "" || customForkVersion {
-				return fmt.Errorf("cannot set %s and %s", config.MainnetFlag.Name, forkVersionFlagNameSet)
+			if err := setNetwork(config.MainnetFlag.Name, network.Mainnet); err != nil {
+				return err
 			}
-			forkVersionFlagNameSet = config.MainnetFlag.Name
-			r.cfg.GenesisForkVersion = relayCommon.GenesisForkVersionMainnet
-			r.genesisTime = relayCommon.GenesisTimeMainnet
 		case config.SepoliaFlag.Name:
-			if forkVersionFlagNameSet != "" || customForkVersion {
-				return fmt.Errorf("cannot set %s and %s", config.SepoliaFlag.Name, forkVersionFlagNameSet)
+			if err := setNetwork(config.SepoliaFlag.Name, network.Sepolia); err != nil {
+				return err
 			}
-			forkVersionFlagNameSet = config.SepoliaFlag.Name
-			r.cfg.GenesisForkVersion = relayCommon.GenesisForkVersionSepolia
-			r.genesisTime = relayCommon.GenesisTimeSepolia
 		case config.GoerliFlag.Name:
-			if forkVersionFlagNameSet != "" || customForkVersion {
-				return fmt.Errorf("cannot set %s and %s", config.GoerliFlag.Name, forkVersionFlagNameSet)
+			if err := setNetwork(config.GoerliFlag.Name, network.Goerli); err != nil {
+				return err
 			}
-			forkVersionFlagNameSet = config.GoerliFlag.Name
-			r.cfg.GenesisForkVersion = relayCommon.GenesisForkVersionGoerli
-			r.genesisTime = relayCommon.GenesisTimeGoerli
+		case config.NetworkFlag.Name:
+			// Applied before the other flags
 		case config.GenesisForkVersionFlag.Name:
 			if forkVersionFlagNameSet != "" && forkVersionFlagNameSet != "custom "+config.GenesisForkVersionFlag.Name {
 				return fmt.Errorf("cannot set custom fork-version flag and %s", forkVersionFlagNameSet)
@@ -346,11 +307,10 @@ func ParseConfigFLags(r *RelayService, moduleFlags commonType.ModuleFlags) error
 			if forkVersionFlagNameSet != "" && forkVersionFlagNameSet != "custom "+config.GenesisForkVersionFlag.Name {
 				return fmt.Errorf("cannot set custom genesis time flag and %s", forkVersionFlagNameSet)
 			}
-			genesisTime, err := strconv.ParseInt(flagValue, 10, 64)
-			if err != nil {
+			// Slot times are kept by the chain clock module, the flag is only checked to go with a custom fork version
+			if _, err := strconv.ParseInt(flagValue, 10, 64); err != nil {
 				return err
 			}
-			r.genesisTime = uint64(genesisTime)
 			customGenesisTime = true
 		case config.GenesisValidatorsRootFlag.Name:
 			r.cfg.GenesisValidatorsRoot = flagValue
@@ -367,11 +327,37 @@ func ParseConfigFLags(r *RelayService, moduleFlags commonType.ModuleFlags) error
 				return err
 			}
 			r.cfg.RequestMaxRetries = int(requestMaxRetries)
+		case config.HealthCheckIntervalMsFlag.Name:
+			healthCheckIntervalMs, err := strconv.ParseInt(flagValue, 10, 64)
+			if err != nil {
+				return err
+			}
+			if healthCheckIntervalMs < 0 {
+				return fmt.Errorf("invalid health check interval %d", healthCheckIntervalMs)
+			}
+			r.cfg.HealthCheckIntervalMs = int(healthCheckIntervalMs)
+		case config.HealthCheckFailureThresholdFlag.Name:
+			healthCheckFailureThreshold, err := strconv.ParseInt(flagValue, 10, 64)
+			if err != nil {
+				return err
+			}
+			if healthCheckFailureThreshold < 1 {
+				return fmt.Errorf("invalid health check failure threshold %d", healthCheckFailureThreshold)
+			}
+			r.cfg.HealthCheckFailureThreshold = int(healthCheckFailureThreshold)
+			r.health.setFailureThreshold(uint64(healthCheckFailureThreshold))
 		default:
 			return fmt.Errorf("invalid flag %s", flagName)
 		}
 	}
 
+	// The relay config file is applied last, so its options replace those of the same relays in the relay entries
+	if path, ok := moduleFlags[config.RelayConfigFlag.Name]; ok {
+		if err := r.loadRelayFile(path); err != nil {
+			return err
+		}
+	}
+
 	if customGenesisTime && forkVersionFlagNameSet != "custom "+config.GenesisTimeFlag.Name && !customForkVersion {
 		return fmt.Errorf("cannot set custom genesis-time flag without custom fork-version flag")
 	}